@@ -32,6 +32,7 @@ import (
 	"github.com/tradalia/portfolio-trader/pkg/app"
 	"github.com/tradalia/portfolio-trader/pkg/core/messaging/inventory"
 	"github.com/tradalia/portfolio-trader/pkg/core/messaging/runtime"
+	"github.com/tradalia/portfolio-trader/pkg/core/messaging/system"
 	"github.com/tradalia/portfolio-trader/pkg/core/process"
 	"github.com/tradalia/portfolio-trader/pkg/db"
 	"github.com/tradalia/portfolio-trader/pkg/platform"
@@ -58,6 +59,7 @@ func main() {
 	process.Init(cfg)
 	inventory.InitMessageListener()
 	runtime.InitMessageListener()
+	system.InitMessageListener()
 	platform.InitPlatform(cfg)
 	boot.RunHttpServer(engine, &cfg.Application)
 }
@@ -36,6 +36,15 @@ type Config struct {
 	core.Authentication
 	core.Platform
 	core.Messaging
+	Monitoring
+}
+
+//=============================================================================
+//--- Parameters of the drawdown envelope check on live trading systems
+
+type Monitoring struct {
+	DrawdownPercentile float64
+	DrawdownRuns       int
 }
 
 //=============================================================================
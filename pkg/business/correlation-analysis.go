@@ -0,0 +1,78 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+package business
+
+import (
+	"time"
+
+	"github.com/tradalia/core/auth"
+	"github.com/tradalia/portfolio-trader/pkg/business/correlation"
+	"github.com/tradalia/portfolio-trader/pkg/core"
+	"github.com/tradalia/portfolio-trader/pkg/db"
+	"gorm.io/gorm"
+)
+
+//=============================================================================
+
+func RunCorrelationAnalysis(tx *gorm.DB, c *auth.Context, req *correlation.AnalysisRequest) (*correlation.AnalysisResponse, error) {
+
+	//--- Get trading systems
+
+	tsMap, err := resolveTradingSystems(tx, c, req.TsIds, req.PortfolioId)
+	if err != nil {
+		return nil, err
+	}
+
+	//--- Daily returns are stored by UTC day
+
+	fromTime, toTime, err := calcPerformancePeriod(req.DaysBack, req.FromDate, req.ToDate, time.UTC)
+	if err != nil {
+		c.Log.Error("RunCorrelationAnalysis: Bad fromDate or toDate", "fromDate", req.FromDate, "toDate", req.ToDate, "error", err)
+		return nil, err
+	}
+
+	returns := &[]db.DailyReturn{}
+	tsIds   := calcIdsArrayFromSourceIds(tsMap)
+
+	if len(tsIds) > 0 {
+		returns, err = db.FindDailyReturnsByTsIdsFromTime(tx, tsIds, fromTime, toTime)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	owner, err := getTradingSystemsOwner(c, tsMap)
+	if err != nil {
+		return nil, err
+	}
+
+	pc, err := core.NewUserProfitCalculator(tx, owner, tsMap)
+	if err != nil {
+		return nil, err
+	}
+
+	return correlation.GetCorrelationAnalysis(tsMap, pc, returns, req.Threshold), nil
+}
+
+//=============================================================================
@@ -0,0 +1,43 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+package correlation
+
+import (
+	"github.com/tradalia/core/datatype"
+)
+
+//=============================================================================
+//--- Trading systems can be provided with a list of ids or with a portfolio.
+//--- In the latter case, systems of all sub portfolios are included
+
+type AnalysisRequest struct {
+	TsIds       []uint           `json:"tsIds"`
+	PortfolioId uint             `json:"portfolioId"`
+	DaysBack    int              `json:"daysBack"  binding:"max=10000"`
+	FromDate    datatype.IntDate `json:"fromDate"`
+	ToDate      datatype.IntDate `json:"toDate"`
+	Threshold   float64          `json:"threshold" binding:"min=0,max=1"`
+}
+
+//=============================================================================
@@ -0,0 +1,69 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+package correlation
+
+//=============================================================================
+
+type TradingSystemInfo struct {
+	Id       uint   `json:"id"`
+	Name     string `json:"name"`
+	FromDate string `json:"fromDate"`
+	ToDate   string `json:"toDate"`
+}
+
+//=============================================================================
+//--- All values are calculated on the days where both systems were trading.
+//--- CoDrawdown is the fraction of days where both systems were in drawdown,
+//--- over the days where at least one was. LosingOverlap is the same for
+//--- losing days. DrawdownCorrelation is the correlation of the drawdown states
+
+type Pair struct {
+	TsId1               uint    `json:"tsId1"`
+	TsId2               uint    `json:"tsId2"`
+	Days                int     `json:"days"`
+	Valid               bool    `json:"valid"`
+	Pearson             float64 `json:"pearson"`
+	Spearman            float64 `json:"spearman"`
+	CoDrawdown          float64 `json:"coDrawdown"`
+	DrawdownCorrelation float64 `json:"drawdownCorrelation"`
+	LosingOverlap       float64 `json:"losingOverlap"`
+}
+
+//=============================================================================
+//--- Matrices follow the order of TradingSystems. Pairs with too few common
+//--- days are not valid and have zero values
+
+type AnalysisResponse struct {
+	TradingSystems []*TradingSystemInfo `json:"tradingSystems"`
+	Threshold      float64              `json:"threshold"`
+	MinCommonDays  int                  `json:"minCommonDays"`
+	Pearson        [][]float64          `json:"pearson"`
+	Spearman       [][]float64          `json:"spearman"`
+	CoDrawdown     [][]float64          `json:"coDrawdown"`
+	LosingOverlap  [][]float64          `json:"losingOverlap"`
+	Pairs          []*Pair              `json:"pairs"`
+	Clusters       [][]uint             `json:"clusters"`
+}
+
+//=============================================================================
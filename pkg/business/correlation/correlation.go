@@ -0,0 +1,282 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+package correlation
+
+import (
+	"sort"
+
+	"github.com/tradalia/portfolio-trader/pkg/core"
+	"github.com/tradalia/portfolio-trader/pkg/core/stats"
+	"github.com/tradalia/portfolio-trader/pkg/db"
+)
+
+//=============================================================================
+
+const (
+	DefaultThreshold = 0.7
+	MinCommonDays    = 20
+)
+
+//=============================================================================
+
+func GetCorrelationAnalysis(tsMap map[uint]*db.TradingSystem, pc *core.ProfitCalculator, returns *[]db.DailyReturn, threshold float64) *AnalysisResponse {
+	if threshold == 0 {
+		threshold = DefaultThreshold
+	}
+
+	ar  := core.BuildAlignedReturns(pc.ConvertDailyReturns(returns), pc)
+	ids := ar.TradingSystemIds()
+
+	res := &AnalysisResponse{
+		TradingSystems: []*TradingSystemInfo{},
+		Threshold     : threshold,
+		MinCommonDays : MinCommonDays,
+		Pearson       : newMatrix(len(ids)),
+		Spearman      : newMatrix(len(ids)),
+		CoDrawdown    : newMatrix(len(ids)),
+		LosingOverlap : newMatrix(len(ids)),
+		Pairs         : []*Pair{},
+	}
+
+	ddMap := map[uint][]bool{}
+
+	for _, id := range ids {
+		res.TradingSystems = append(res.TradingSystems, &TradingSystemInfo{
+			Id      : id,
+			Name    : tsMap[id].Name,
+			FromDate: ar.Days[ar.First[id]].String(),
+			ToDate  : ar.Days[ar.Last [id]].String(),
+		})
+
+		ddMap[id] = buildDrawdownStates(ar.Returns[id], ar.First[id], ar.Last[id])
+	}
+
+	for i, id1 := range ids {
+		for j := i; j < len(ids); j++ {
+			id2 := ids[j]
+
+			if i == j {
+				res.Pearson      [i][i] = 1
+				res.Spearman     [i][i] = 1
+				res.CoDrawdown   [i][i] = 1
+				res.LosingOverlap[i][i] = 1
+				continue
+			}
+
+			pair := calcPair(ar, ddMap, id1, id2)
+			res.Pairs = append(res.Pairs, pair)
+
+			setSymmetric(res.Pearson,       i, j, pair.Pearson)
+			setSymmetric(res.Spearman,      i, j, pair.Spearman)
+			setSymmetric(res.CoDrawdown,    i, j, pair.CoDrawdown)
+			setSymmetric(res.LosingOverlap, i, j, pair.LosingOverlap)
+		}
+	}
+
+	res.Clusters = buildClusters(ids, res.Pairs, threshold)
+
+	return res
+}
+
+//=============================================================================
+//===
+//=== Private functions
+//===
+//=============================================================================
+
+func calcPair(ar *core.AlignedReturns, ddMap map[uint][]bool, id1, id2 uint) *Pair {
+	pair := &Pair{
+		TsId1: id1,
+		TsId2: id2,
+	}
+
+	from, to := ar.CommonRange(id1, id2)
+	if to - from + 1 < MinCommonDays {
+		pair.Days = max(to - from + 1, 0)
+		return pair
+	}
+
+	ret1 := ar.Returns[id1][from:to+1]
+	ret2 := ar.Returns[id2][from:to+1]
+	dd1  := ddMap[id1][from:to+1]
+	dd2  := ddMap[id2][from:to+1]
+
+	pair.Days     = len(ret1)
+	pair.Valid    = true
+	pair.Pearson  = core.Trunc2d(stats.Correlation        (ret1, ret2))
+	pair.Spearman = core.Trunc2d(stats.SpearmanCorrelation(ret1, ret2))
+
+	pair.CoDrawdown          = core.Trunc2d(calcOverlap(dd1, dd2))
+	pair.DrawdownCorrelation = core.Trunc2d(stats.Correlation(toFloats(dd1), toFloats(dd2)))
+	pair.LosingOverlap       = core.Trunc2d(calcOverlap(toLosing(ret1), toLosing(ret2)))
+
+	return pair
+}
+
+//=============================================================================
+//--- The equity starts from zero on the first day of the system
+
+func buildDrawdownStates(returns []float64, first, last int) []bool {
+	states := make([]bool, len(returns))
+	equity := 0.0
+	peak   := 0.0
+
+	for i := first; i <= last; i++ {
+		equity += returns[i]
+
+		if equity >= peak {
+			peak = equity
+		} else {
+			states[i] = true
+		}
+	}
+
+	return states
+}
+
+//=============================================================================
+//--- Days where both are true over days where at least one is true
+
+func calcOverlap(x, y []bool) float64 {
+	both   := 0
+	either := 0
+
+	for i := range x {
+		if x[i] && y[i] {
+			both++
+		}
+
+		if x[i] || y[i] {
+			either++
+		}
+	}
+
+	if either == 0 {
+		return 0
+	}
+
+	return float64(both) / float64(either)
+}
+
+//=============================================================================
+
+func toLosing(returns []float64) []bool {
+	res := make([]bool, len(returns))
+
+	for i, value := range returns {
+		res[i] = value < 0
+	}
+
+	return res
+}
+
+//=============================================================================
+
+func toFloats(states []bool) []float64 {
+	res := make([]float64, len(states))
+
+	for i, state := range states {
+		if state {
+			res[i] = 1
+		}
+	}
+
+	return res
+}
+
+//=============================================================================
+//--- Single linkage clustering: systems are in the same cluster if they are
+//--- connected by a chain of pairs with a pearson correlation above the threshold.
+//--- Clusters with only one system are not returned
+
+func buildClusters(ids []uint, pairs []*Pair, threshold float64) [][]uint {
+	parent := map[uint]uint{}
+	for _, id := range ids {
+		parent[id] = id
+	}
+
+	var find func(id uint) uint
+	find = func(id uint) uint {
+		if parent[id] != id {
+			parent[id] = find(parent[id])
+		}
+
+		return parent[id]
+	}
+
+	for _, pair := range pairs {
+		if pair.Valid && pair.Pearson >= threshold {
+			root1 := find(pair.TsId1)
+			root2 := find(pair.TsId2)
+
+			if root1 != root2 {
+				parent[max(root1, root2)] = min(root1, root2)
+			}
+		}
+	}
+
+	groupMap := map[uint][]uint{}
+	for _, id := range ids {
+		root := find(id)
+		groupMap[root] = append(groupMap[root], id)
+	}
+
+	clusters := [][]uint{}
+	for _, group := range groupMap {
+		if len(group) > 1 {
+			clusters = append(clusters, group)
+		}
+	}
+
+	sort.Slice(clusters, func(i, j int) bool {
+		if len(clusters[i]) != len(clusters[j]) {
+			return len(clusters[i]) > len(clusters[j])
+		}
+
+		return clusters[i][0] < clusters[j][0]
+	})
+
+	return clusters
+}
+
+//=============================================================================
+
+func newMatrix(size int) [][]float64 {
+	m := make([][]float64, size)
+
+	for i := range m {
+		m[i] = make([]float64, size)
+	}
+
+	return m
+}
+
+//=============================================================================
+
+func setSymmetric(m [][]float64, i, j int, value float64) {
+	m[i][j] = value
+	m[j][i] = value
+}
+
+//=============================================================================
@@ -0,0 +1,91 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+
+
+package correlation
+
+import (
+	"reflect"
+	"testing"
+)
+
+//=============================================================================
+
+func TestBuildClusters(t *testing.T) {
+	ids := []uint{ 1, 2, 3, 4, 5, 6 }
+
+	//--- 1-2 and 2-3 are chained, 4-5 is below the threshold, 5-6 is not valid
+
+	pairs := []*Pair{
+		{ TsId1: 1, TsId2: 2, Valid: true,  Pearson: 0.8 },
+		{ TsId1: 2, TsId2: 3, Valid: true,  Pearson: 0.7 },
+		{ TsId1: 1, TsId2: 3, Valid: true,  Pearson: 0.1 },
+		{ TsId1: 4, TsId2: 5, Valid: true,  Pearson: 0.5 },
+		{ TsId1: 5, TsId2: 6, Valid: false, Pearson: 0.9 },
+	}
+
+	clusters := buildClusters(ids, pairs, 0.7)
+	expected := [][]uint{ { 1, 2, 3 } }
+
+	if !reflect.DeepEqual(clusters, expected) {
+		t.Errorf("Bad clusters: Expected %v and got %v", expected, clusters)
+	}
+
+	//--- Larger clusters come first
+
+	pairs[3].Pearson = 0.9
+	pairs[0].Pearson = 0.1
+
+	clusters = buildClusters(ids, pairs, 0.7)
+	expected = [][]uint{ { 2, 3 }, { 4, 5 } }
+
+	if !reflect.DeepEqual(clusters, expected) {
+		t.Errorf("Bad clusters: Expected %v and got %v", expected, clusters)
+	}
+
+	clusters = buildClusters(ids, nil, 0.7)
+
+	if len(clusters) != 0 {
+		t.Errorf("Bad clusters: Expected none and got %v", clusters)
+	}
+}
+
+//=============================================================================
+
+func TestBuildDrawdownStates(t *testing.T) {
+	returns  := []float64{ 9, 5, -2, 1, 3, -1, 0 }
+	states   := buildDrawdownStates(returns, 1, 5)
+	expected := []bool{ false, false, true, true, false, true, false }
+
+	if !reflect.DeepEqual(states, expected) {
+		t.Errorf("Bad drawdown states: Expected %v and got %v", expected, states)
+	}
+
+	overlap := calcOverlap(states, toLosing(returns))
+	if overlap != 2.0 / 3.0 {
+		t.Errorf("Bad overlap: Expected 0.66 and got %v", overlap)
+	}
+}
+
+//=============================================================================
@@ -0,0 +1,241 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+
+
+package business
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tradalia/core/auth"
+	"github.com/tradalia/core/datatype"
+	"github.com/tradalia/core/req"
+	"github.com/tradalia/portfolio-trader/pkg/db"
+	"gorm.io/gorm"
+)
+
+//=============================================================================
+
+type UserSettingRequest struct {
+	BaseCurrency string `json:"baseCurrency"`
+}
+
+//=============================================================================
+
+type FxRateImportResponse struct {
+	Imported int `json:"imported"`
+}
+
+//=============================================================================
+
+var currencyRegexp = regexp.MustCompile("^[A-Z]{3}$")
+
+//=============================================================================
+//===
+//=== User settings
+//===
+//=============================================================================
+
+func GetUserSetting(tx *gorm.DB, c *auth.Context) (*db.UserSetting, error) {
+	us, err := db.GetUserSetting(tx, c.Session.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	if us == nil {
+		us = &db.UserSetting{
+			Username: c.Session.Username,
+		}
+	}
+
+	return us, nil
+}
+
+//=============================================================================
+//--- An empty base currency disables the conversion
+
+func SetUserSetting(tx *gorm.DB, c *auth.Context, usr *UserSettingRequest) (*db.UserSetting, error) {
+	currency := strings.ToUpper(strings.TrimSpace(usr.BaseCurrency))
+
+	if currency != "" && !currencyRegexp.MatchString(currency) {
+		return nil, req.NewBadRequestError("Invalid currency code: %v", usr.BaseCurrency)
+	}
+
+	us := &db.UserSetting{
+		Username    : c.Session.Username,
+		BaseCurrency: currency,
+	}
+
+	err := db.SetUserSetting(tx, us)
+	if err != nil {
+		return nil, req.NewServerErrorByError(err)
+	}
+
+	c.Log.Info("SetUserSetting: Settings updated", "baseCurrency", currency)
+	return us, nil
+}
+
+//=============================================================================
+//===
+//=== FX rates
+//===
+//=============================================================================
+
+func GetFxRates(tx *gorm.DB, c *auth.Context, filter map[string]any, offset int, limit int) (*[]db.FxRate, error) {
+	return db.GetFxRates(tx, filter, offset, limit)
+}
+
+//=============================================================================
+//--- Expected columns are: day (yyyymmdd or yyyy-mm-dd), base currency, quote
+//--- currency and rate. A header line is skipped
+
+func ImportFxRates(tx *gorm.DB, c *auth.Context, data []byte) (*FxRateImportResponse, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord  = 4
+
+	var list []db.FxRate
+
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, req.NewBadRequestError("Bad CSV format: %v", err.Error())
+		}
+
+		if line == 1 && strings.EqualFold(strings.TrimSpace(record[0]), "day") {
+			continue
+		}
+
+		rate, err := parseFxRate(record)
+		if err != nil {
+			return nil, req.NewBadRequestError("Bad FX rate at line %v: %v", line, err.Error())
+		}
+
+		list = append(list, *rate)
+	}
+
+	err := SetFxRates(tx, list)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Log.Info("ImportFxRates: FX rates imported", "count", len(list))
+
+	return &FxRateImportResponse{
+		Imported: len(list),
+	}, nil
+}
+
+//=============================================================================
+
+func SetFxRates(tx *gorm.DB, list []db.FxRate) error {
+	for _, r := range list {
+		err := ValidateFxRate(&r)
+		if err != nil {
+			return req.NewBadRequestError("Bad FX rate (%v %v/%v): %v", r.Day, r.BaseCurrency, r.QuoteCurrency, err.Error())
+		}
+	}
+
+	err := db.SetFxRates(tx, list)
+	if err != nil {
+		return req.NewServerErrorByError(err)
+	}
+
+	return nil
+}
+
+//=============================================================================
+
+func ValidateFxRate(r *db.FxRate) error {
+	if !r.Day.IsValid() {
+		return errors.New("invalid day")
+	}
+
+	if !currencyRegexp.MatchString(r.BaseCurrency) || !currencyRegexp.MatchString(r.QuoteCurrency) {
+		return errors.New("invalid currency code")
+	}
+
+	if r.BaseCurrency == r.QuoteCurrency {
+		return errors.New("base and quote currencies must differ")
+	}
+
+	if r.Rate <= 0 {
+		return errors.New("rate must be positive")
+	}
+
+	return nil
+}
+
+//=============================================================================
+//===
+//=== Private methods
+//===
+//=============================================================================
+
+func parseFxRate(record []string) (*db.FxRate, error) {
+	day, err := parseFxDay(strings.TrimSpace(record[0]))
+	if err != nil {
+		return nil, err
+	}
+
+	rate, err := strconv.ParseFloat(strings.TrimSpace(record[3]), 64)
+	if err != nil {
+		return nil, errors.New("invalid rate")
+	}
+
+	return &db.FxRate{
+		Day          : day,
+		BaseCurrency : strings.ToUpper(strings.TrimSpace(record[1])),
+		QuoteCurrency: strings.ToUpper(strings.TrimSpace(record[2])),
+		Rate         : rate,
+	}, nil
+}
+
+//=============================================================================
+
+func parseFxDay(value string) (datatype.IntDate, error) {
+	if strings.Contains(value, "-") {
+		t, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			return 0, errors.New("invalid day")
+		}
+
+		return datatype.ToIntDate(&t), nil
+	}
+
+	return datatype.ParseIntDate(value, true)
+}
+
+//=============================================================================
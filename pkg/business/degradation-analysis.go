@@ -0,0 +1,63 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+
+package business
+
+import (
+	"github.com/tradalia/core/auth"
+	"github.com/tradalia/portfolio-trader/pkg/business/degradation"
+	"github.com/tradalia/portfolio-trader/pkg/core"
+	"github.com/tradalia/portfolio-trader/pkg/db"
+	"gorm.io/gorm"
+)
+
+//=============================================================================
+
+func RunDegradationAnalysis(tx *gorm.DB, c *auth.Context, tsId uint, req *degradation.AnalysisRequest) (*degradation.AnalysisResponse, error) {
+
+	//--- Get trading system
+
+	ts, err := getTradingSystemAndCheckAccess(tx, c, tsId)
+	if err != nil {
+		return nil, err
+	}
+
+	//--- Periods are expressed in the exchange's timezone
+
+	loc, err := core.GetLocation("exchange", ts)
+	if err != nil {
+		c.Log.Error("RunDegradationAnalysis: Bad trading system timezone", "timezone", ts.Timezone, "error", err)
+		return nil, err
+	}
+
+	trades, err := db.FindTradesByTradingSystemId(tx, ts.Id)
+	if err != nil {
+		return nil,err
+	}
+	shiftTradesTimezone(trades, loc)
+
+	return degradation.GetDegradationAnalysis(ts, trades, req.LiveFrom, loc)
+}
+
+//=============================================================================
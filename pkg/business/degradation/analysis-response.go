@@ -0,0 +1,95 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+
+package degradation
+
+import (
+	"github.com/tradalia/core/datatype"
+	"github.com/tradalia/portfolio-trader/pkg/db"
+)
+
+//=============================================================================
+
+const (
+	FlagAverageTrade   = "averageTrade"
+	FlagWinPercentage  = "winPercentage"
+	FlagTradeFrequency = "tradeFrequency"
+	FlagDistribution   = "distribution"
+)
+
+//=============================================================================
+
+type AnalysisResponse struct {
+	TradingSystem  *db.TradingSystem `json:"tradingSystem"`
+	Alpha          float64           `json:"alpha"`
+	Development    *PeriodStats      `json:"development"`
+	OutOfSample    *PeriodStats      `json:"outOfSample"`
+	Live           *PeriodStats      `json:"live"`
+	VsDevelopment  *Comparison       `json:"vsDevelopment"`
+	VsOutOfSample  *Comparison       `json:"vsOutOfSample"`
+	Deteriorated   bool              `json:"deteriorated"`
+}
+
+//=============================================================================
+
+type PeriodStats struct {
+	From           datatype.IntDate `json:"from"`
+	To             datatype.IntDate `json:"to"`
+	Trades         int              `json:"trades"`
+	NetProfit      float64          `json:"netProfit"`
+	AverageTrade   float64          `json:"averageTrade"`
+	StandardDev    float64          `json:"standardDev"`
+	WinPerc        float64          `json:"winPerc"`
+	TradesPerMonth float64          `json:"tradesPerMonth"`
+	PayoffRatio    float64          `json:"payoffRatio"`
+	MaxDrawdown    float64          `json:"maxDrawdown"`
+
+	profits        []float64
+	winners        int
+	months         float64
+}
+
+//=============================================================================
+//--- Tests are one-sided on the hypothesis that the live period is worse, except
+//--- the distribution one (KS) which is two-sided
+
+type Comparison struct {
+	AverageTrade     *TestResult `json:"averageTrade"`
+	WinPerc          *TestResult `json:"winPerc"`
+	TradeFrequency   *TestResult `json:"tradeFrequency"`
+	Distribution     *TestResult `json:"distribution"`
+	PayoffRatioRatio float64     `json:"payoffRatioRatio"`
+	DrawdownRatio    float64     `json:"drawdownRatio"`
+	Flags            []string    `json:"flags"`
+}
+
+//=============================================================================
+
+type TestResult struct {
+	Statistic   float64 `json:"statistic"`
+	PValue      float64 `json:"pValue"`
+	Significant bool    `json:"significant"`
+}
+
+//=============================================================================
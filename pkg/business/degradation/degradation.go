@@ -0,0 +1,268 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+
+package degradation
+
+import (
+	"math"
+	"time"
+
+	"github.com/tradalia/core/datatype"
+	"github.com/tradalia/core/req"
+	"github.com/tradalia/portfolio-trader/pkg/core"
+	"github.com/tradalia/portfolio-trader/pkg/core/stats"
+	"github.com/tradalia/portfolio-trader/pkg/db"
+)
+
+//=============================================================================
+
+const (
+	Alpha             = 0.05
+	MinTradesForTests = 5
+	DaysPerMonth      = 30.4375
+)
+
+//=============================================================================
+//--- Trades are assigned to a period using the entry day, so they must be
+//--- already shifted into the trading system's timezone
+
+func GetDegradationAnalysis(ts *db.TradingSystem, trades *[]db.Trade, liveFrom datatype.IntDate, loc *time.Location) (*AnalysisResponse, error) {
+	if !ts.Running {
+		return nil, req.NewUnprocessableEntityError("Trading system is not running: %v", ts.Id)
+	}
+
+	if ts.InSampleFrom.IsNil() || ts.InSampleTo.IsNil() {
+		return nil, req.NewUnprocessableEntityError("Trading system has no development period: %v", ts.Id)
+	}
+
+	if liveFrom.IsNil() {
+		liveFrom = ts.LiveFrom
+		if liveFrom.IsNil() {
+			return nil, req.NewUnprocessableEntityError("Trading system has no live start date (set it when it starts running or pass liveFrom): %v", ts.Id)
+		}
+	}
+
+	if liveFrom <= ts.InSampleTo {
+		return nil, req.NewBadRequestError("Live period must start after the development period (%v): %v", ts.InSampleTo, liveFrom)
+	}
+
+	res := &AnalysisResponse{
+		TradingSystem: ts,
+		Alpha        : Alpha,
+		Development  : newPeriodStats(ts.InSampleFrom,          ts.InSampleTo),
+		OutOfSample  : newPeriodStats(ts.InSampleTo.AddDays(1), liveFrom.AddDays(-1)),
+		Live         : newPeriodStats(liveFrom,                 datatype.Today(loc)),
+	}
+
+	for _, tr := range *trades {
+		day := datatype.ToIntDate(tr.EntryDate)
+		net := tr.GrossProfit - 2 * ts.CostPerOperation
+
+		switch {
+			case day >= ts.InSampleFrom && day <= ts.InSampleTo:
+				res.Development.addTrade(net)
+			case day > ts.InSampleTo && day < liveFrom:
+				res.OutOfSample.addTrade(net)
+			case day >= liveFrom:
+				res.Live.addTrade(net)
+		}
+	}
+
+	res.Development.consolidate()
+	res.OutOfSample.consolidate()
+	res.Live       .consolidate()
+
+	res.VsDevelopment = compare(res.Live, res.Development)
+	res.VsOutOfSample = compare(res.Live, res.OutOfSample)
+
+	res.Deteriorated = (res.VsDevelopment != nil && len(res.VsDevelopment.Flags) > 0) ||
+					   (res.VsOutOfSample != nil && len(res.VsOutOfSample.Flags) > 0)
+
+	return res, nil
+}
+
+//=============================================================================
+//===
+//=== Private functions
+//===
+//=============================================================================
+
+func newPeriodStats(from, to datatype.IntDate) *PeriodStats {
+	ps := &PeriodStats{
+		From: from,
+		To  : to,
+	}
+
+	if to >= from {
+		days := to.ToDateTime(true, time.UTC).Sub(from.ToDateTime(false, time.UTC)).Hours() / 24
+		ps.months = days / DaysPerMonth
+	}
+
+	return ps
+}
+
+//=============================================================================
+//--- Returns the result of a comparison between the live period and a
+//--- reference one. Returns nil if there are not enough trades
+
+func compare(live, ref *PeriodStats) *Comparison {
+	if live.Trades < MinTradesForTests || ref.Trades < MinTradesForTests {
+		return nil
+	}
+
+	c := &Comparison{
+		Flags: []string{},
+	}
+
+	//--- Average trade
+
+	t, p := stats.WelchTTest(live.profits, ref.profits)
+	c.AverageTrade = newTestResult(t, p, FlagAverageTrade, c)
+
+	//--- Winning percentage
+
+	z, p := stats.TwoProportionZTest(live.winners, live.Trades, ref.winners, ref.Trades)
+	c.WinPerc = newTestResult(z, p, FlagWinPercentage, c)
+
+	//--- Trade frequency
+
+	z, p = testTradeFrequency(live, ref)
+	c.TradeFrequency = newTestResult(z, p, FlagTradeFrequency, c)
+
+	//--- Distribution of trade results
+
+	d, p := stats.KolmogorovSmirnovTest(live.profits, ref.profits)
+	c.Distribution = newTestResult(d, p, FlagDistribution, c)
+
+	//--- Ratios
+
+	if ref.PayoffRatio != 0 {
+		c.PayoffRatioRatio = core.Trunc2d(live.PayoffRatio / ref.PayoffRatio)
+	}
+
+	if ref.MaxDrawdown != 0 {
+		c.DrawdownRatio = core.Trunc2d(live.MaxDrawdown / ref.MaxDrawdown)
+	}
+
+	return c
+}
+
+//=============================================================================
+
+func newTestResult(statistic, pValue float64, flag string, c *Comparison) *TestResult {
+	tr := &TestResult{
+		Statistic  : core.TruncFinite(statistic),
+		PValue     : pValue,
+		Significant: pValue < Alpha,
+	}
+
+	if tr.Significant {
+		c.Flags = append(c.Flags, flag)
+	}
+
+	return tr
+}
+
+//=============================================================================
+//--- Conditional test on 2 poisson rates: given the total number of trades, the
+//--- live ones follow a binomial distribution with p = live time / total time.
+//--- Uses the normal approximation, one-sided on the hypothesis live rate < ref rate
+
+func testTradeFrequency(live, ref *PeriodStats) (float64, float64) {
+	if live.months <= 0 || ref.months <= 0 {
+		return 0, 1
+	}
+
+	n  := float64(live.Trades + ref.Trades)
+	p0 := live.months / (live.months + ref.months)
+	sd := math.Sqrt(n * p0 * (1 - p0))
+
+	if sd == 0 {
+		return 0, 1
+	}
+
+	z := (float64(live.Trades) - n * p0) / sd
+
+	return z, stats.NormalCdf(z)
+}
+
+//=============================================================================
+//===
+//=== PeriodStats methods
+//===
+//=============================================================================
+
+func (ps *PeriodStats) addTrade(net float64) {
+	ps.profits = append(ps.profits, net)
+	ps.Trades++
+
+	if net > 0 {
+		ps.winners++
+	}
+}
+
+//=============================================================================
+
+func (ps *PeriodStats) consolidate() {
+	if ps.Trades == 0 {
+		return
+	}
+
+	winSum  := 0.0
+	lossSum := 0.0
+
+	for _, p := range ps.profits {
+		ps.NetProfit += p
+
+		if p > 0 {
+			winSum += p
+		} else if p < 0 {
+			lossSum -= p
+		}
+	}
+
+	mean := ps.NetProfit / float64(ps.Trades)
+
+	ps.AverageTrade = core.Trunc2d(mean)
+	ps.NetProfit    = core.Trunc2d(ps.NetProfit)
+	ps.WinPerc      = core.Trunc2d(float64(ps.winners) * 100 / float64(ps.Trades))
+
+	if ps.Trades > 1 {
+		ps.StandardDev = core.Trunc2d(stats.SampleStdDev(ps.profits, mean))
+	}
+
+	if ps.months > 0 {
+		ps.TradesPerMonth = core.Trunc2d(float64(ps.Trades) / ps.months)
+	}
+
+	losers := ps.Trades - ps.winners
+	if ps.winners > 0 && losers > 0 && lossSum > 0 {
+		ps.PayoffRatio = core.Trunc2d((winSum / float64(ps.winners)) / (lossSum / float64(losers)))
+	}
+
+	_, maxDD := core.BuildDrawDown(core.BuildEquity(&ps.profits))
+	ps.MaxDrawdown = core.Trunc2d(maxDD)
+}
+
+//=============================================================================
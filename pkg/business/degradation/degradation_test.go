@@ -0,0 +1,101 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+
+
+package degradation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tradalia/portfolio-trader/pkg/db"
+	"github.com/tradalia/portfolio-trader/pkg/db/dbtest"
+)
+
+//=============================================================================
+
+func TestGetDegradationAnalysis(t *testing.T) {
+	ts := &db.TradingSystem{ Id: 1, Running: true, InSampleFrom: 20250101, InSampleTo: 20250110, LiveFrom: 20250120 }
+
+	//--- Development on days 1..6, out of sample on days 13..17, live on days 20..24
+
+	trades := []db.Trade{}
+	for _, day := range []int{ 1, 2, 3, 4, 5, 6, 13, 14, 15, 16, 17, 20, 21, 22, 23, 24 } {
+		profit := 100.0
+		if day % 2 == 0 {
+			profit = -40
+		}
+
+		trades = append(trades, dbtest.NewTrade(1, db.TradeTypeLong, dbtest.Time(day, 10), dbtest.Time(day, 15), profit, 1))
+	}
+
+	res, err := GetDegradationAnalysis(ts, &trades, 0, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Development.Trades != 6 || res.OutOfSample.Trades != 5 || res.Live.Trades != 5 {
+		t.Errorf("Bad periods: Expected 6, 5, 5 trades and got %v, %v, %v", res.Development.Trades, res.OutOfSample.Trades, res.Live.Trades)
+	}
+
+	if res.OutOfSample.From != 20250111 || res.OutOfSample.To != 20250119 || res.Live.From != 20250120 {
+		t.Errorf("Bad periods: Expected out of sample 20250111..20250119 and live from 20250120 and got %v..%v and %v", res.OutOfSample.From, res.OutOfSample.To, res.Live.From)
+	}
+
+	if res.VsDevelopment == nil || res.VsOutOfSample == nil {
+		t.Errorf("Bad comparisons: Expected both and got %+v, %+v", res.VsDevelopment, res.VsOutOfSample)
+	}
+
+	//--- An explicit live start overrides the stored one
+
+	res, err = GetDegradationAnalysis(ts, &trades, 20250115, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.OutOfSample.Trades != 2 || res.Live.Trades != 8 {
+		t.Errorf("Bad periods: Expected 2, 8 trades and got %v, %v", res.OutOfSample.Trades, res.Live.Trades)
+	}
+}
+
+//=============================================================================
+
+func TestGetDegradationAnalysisLiveStart(t *testing.T) {
+	ts     := &db.TradingSystem{ Id: 1, Running: true, InSampleFrom: 20250101, InSampleTo: 20250110 }
+	trades := []db.Trade{}
+
+	if _, err := GetDegradationAnalysis(ts, &trades, 0, time.UTC); err == nil {
+		t.Errorf("Bad analysis: Expected an error without a live start")
+	}
+
+	if _, err := GetDegradationAnalysis(ts, &trades, 20250110, time.UTC); err == nil {
+		t.Errorf("Bad analysis: Expected an error with a live start inside the development period")
+	}
+
+	if _, err := GetDegradationAnalysis(ts, &trades, 20250111, time.UTC); err != nil {
+		t.Errorf("Bad analysis: Expected no error and got %v", err)
+	}
+}
+
+//=============================================================================
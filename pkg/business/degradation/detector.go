@@ -0,0 +1,181 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+
+package degradation
+
+import (
+	"time"
+
+	"github.com/tradalia/core/datatype"
+	"github.com/tradalia/portfolio-trader/pkg/core"
+	"github.com/tradalia/portfolio-trader/pkg/core/stats"
+	"github.com/tradalia/portfolio-trader/pkg/db"
+)
+
+//=============================================================================
+//--- CUSUM parameters: allowance and decision limit are expressed in standard
+//--- deviations of the development period's net trades
+
+const (
+	CusumAllowance    = 0.5
+	CusumLimit        = 5.0
+	CusumMinDevTrades = 30
+)
+
+//=============================================================================
+
+type CusumResult struct {
+	Alarm     bool
+	Date      *time.Time
+	Statistic float64
+	Limit     float64
+}
+
+//=============================================================================
+//--- Runs a lower CUSUM on the live net trades, using the development period
+//--- (InSampleFrom..InSampleTo) as the reference. Returns nil if the check
+//--- cannot be done. The alarm date is the exit of the trade that made the
+//--- statistic cross the limit in the current excursion
+
+func RunCusum(ts *db.TradingSystem, trades *[]db.Trade) *CusumResult {
+	devList, liveList, liveExits := splitTrades(ts, trades)
+
+	if len(devList) < CusumMinDevTrades || len(liveList) == 0 {
+		return nil
+	}
+
+	mean   := stats.Mean(devList)
+	stdDev := stats.SampleStdDev(devList, mean)
+	cusum  := stats.LowerCusum(liveList, mean, stdDev, CusumAllowance)
+	last   := len(cusum) -1
+
+	dc := &CusumResult{
+		Alarm    : cusum[last] >= CusumLimit,
+		Statistic: core.Trunc2d(cusum[last]),
+		Limit    : CusumLimit,
+	}
+
+	if dc.Alarm {
+		i := last
+		for i > 0 && cusum[i -1] >= CusumLimit {
+			i--
+		}
+
+		dc.Date = liveExits[i]
+	}
+
+	return dc
+}
+
+//=============================================================================
+//--- Sets or clears the degraded status. Only running systems are considered.
+//--- Idle and broken systems keep their status (it has precedence) and are
+//--- degraded when they start trading again. A suggestion coming from the
+//--- trading filter is never replaced
+
+func UpdateStatus(ts *db.TradingSystem, trades *[]db.Trade) {
+	if !ts.Running {
+		return
+	}
+
+	dc := RunCusum(ts, trades)
+
+	if dc != nil && dc.Alarm {
+		if ts.Status == db.TsStatusRunning || ts.Status == db.TsStatusPaused {
+			ts.Status = db.TsStatusDegraded
+		}
+
+		if ts.SuggestedAction == db.TsActionNone {
+			ts.SuggestedAction = db.TsActionCheck
+		}
+
+		ts.DegradationDate  = dc.Date
+		ts.DegradationStat  = dc.Statistic
+		ts.DegradationLimit = dc.Limit
+		return
+	}
+
+	if ts.Status == db.TsStatusDegraded {
+		if ts.Active {
+			ts.Status = db.TsStatusRunning
+		} else {
+			ts.Status = db.TsStatusPaused
+		}
+
+		if ts.SuggestedAction == db.TsActionCheck && !ts.EnvelopeExceeded {
+			ts.SuggestedAction = db.TsActionNone
+		}
+	}
+
+	ts.DegradationDate  = nil
+	ts.DegradationStat  = 0
+	ts.DegradationLimit = 0
+
+	if dc != nil {
+		ts.DegradationStat  = dc.Statistic
+		ts.DegradationLimit = dc.Limit
+	}
+}
+
+//=============================================================================
+//===
+//=== Private functions
+//===
+//=============================================================================
+//--- Splits the net trades into the development period (InSampleFrom..InSampleTo)
+//--- and the live one (from LiveFrom). Out of sample trades in between are
+//--- skipped. Days are calculated in the exchange's timezone. Returns empty
+//--- lists if the periods cannot be determined
+
+func splitTrades(ts *db.TradingSystem, trades *[]db.Trade) ([]float64, []float64, []*time.Time) {
+	var devList   []float64
+	var liveList  []float64
+	var liveExits []*time.Time
+
+	if ts.InSampleFrom.IsNil() || ts.InSampleTo.IsNil() || ts.LiveFrom.IsNil() {
+		return devList, liveList, liveExits
+	}
+
+	loc, err := time.LoadLocation(ts.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	for _, tr := range *trades {
+		entry := tr.EntryDate.In(loc)
+		day   := datatype.ToIntDate(&entry)
+		net   := tr.GrossProfit - 2 * ts.CostPerOperation
+
+		if day >= ts.InSampleFrom && day <= ts.InSampleTo {
+			devList = append(devList, net)
+		} else if day > ts.InSampleTo && day >= ts.LiveFrom {
+			liveList  = append(liveList,  net)
+			liveExits = append(liveExits, tr.ExitDate)
+		}
+	}
+
+	return devList, liveList, liveExits
+}
+
+//=============================================================================
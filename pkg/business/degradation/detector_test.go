@@ -0,0 +1,62 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+
+
+package degradation
+
+import (
+	"testing"
+
+	"github.com/tradalia/portfolio-trader/pkg/db"
+	"github.com/tradalia/portfolio-trader/pkg/db/dbtest"
+)
+
+//=============================================================================
+
+func TestSplitTrades(t *testing.T) {
+	ts := &db.TradingSystem{ Id: 1, InSampleFrom: 20250101, InSampleTo: 20250110, Timezone: "UTC" }
+
+	trades := []db.Trade{
+		dbtest.NewTrade(1, db.TradeTypeLong, dbtest.Time( 2, 10), dbtest.Time( 2, 15),  10, 1),
+		dbtest.NewTrade(1, db.TradeTypeLong, dbtest.Time( 8, 10), dbtest.Time( 8, 15), -20, 1),
+		dbtest.NewTrade(1, db.TradeTypeLong, dbtest.Time(14, 10), dbtest.Time(14, 15),  30, 1),
+		dbtest.NewTrade(1, db.TradeTypeLong, dbtest.Time(21, 10), dbtest.Time(21, 15), -40, 1),
+	}
+
+	//--- Without a live start, nothing is live (not even the out of sample trades)
+
+	devList, liveList, _ := splitTrades(ts, &trades)
+	if len(devList) != 0 || len(liveList) != 0 {
+		t.Errorf("Bad split: Expected no trades and got %v, %v", devList, liveList)
+	}
+
+	ts.LiveFrom = 20250120
+
+	devList, liveList, liveExits := splitTrades(ts, &trades)
+	if len(devList) != 2 || len(liveList) != 1 || liveList[0] != -40 || !liveExits[0].Equal(dbtest.Time(21, 15)) {
+		t.Errorf("Bad split: Expected 2 development trades and the last one live and got %v, %v", devList, liveList)
+	}
+}
+
+//=============================================================================
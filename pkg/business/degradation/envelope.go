@@ -0,0 +1,132 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+
+package degradation
+
+import (
+	"github.com/tradalia/portfolio-trader/pkg/business/simulation"
+	"github.com/tradalia/portfolio-trader/pkg/core"
+	"github.com/tradalia/portfolio-trader/pkg/core/stats"
+	"github.com/tradalia/portfolio-trader/pkg/db"
+)
+
+//=============================================================================
+
+const EnvelopeMinDevTrades = 30
+
+//=============================================================================
+
+type EnvelopeResult struct {
+	LiveDrawdown  float64
+	LiveDuration  int
+	DepthPerc     float64
+	DurationPerc  float64
+	DepthLimit    float64
+	DurationLimit int
+	Exceeded      bool
+}
+
+//=============================================================================
+//--- Compares the live max drawdown (depth and duration in trades) with the
+//--- distribution obtained by resampling the development trades into sequences
+//--- as long as the live period, which starts at LiveFrom. Percentiles tell how
+//--- many simulated drawdowns are less severe than the live one. Returns nil if
+//--- the check cannot be done
+
+func CheckDrawdownEnvelope(ts *db.TradingSystem, trades *[]db.Trade, percentile float64, runs int) *EnvelopeResult {
+	devList, liveList, _ := splitTrades(ts, trades)
+
+	if len(devList) < EnvelopeMinDevTrades || len(liveList) == 0 {
+		return nil
+	}
+
+	drawDown, maxDD := core.BuildDrawDown(core.BuildEquity(&liveList))
+	duration        := core.CalcMaxDrawDownDuration(drawDown)
+
+	simDepths, simDurations := simulation.SampleDrawdowns(devList, runs, len(liveList))
+
+	//--- Drawdowns are negative: use depths to have higher values for worse cases
+
+	depths    := make([]float64, len(simDepths))
+	durations := make([]float64, len(simDurations))
+
+	for i := range simDepths {
+		depths   [i] = -simDepths[i]
+		durations[i] = float64(simDurations[i])
+	}
+
+	depthPerc    := stats.NewPercentile(depths)
+	durationPerc := stats.NewPercentile(durations)
+
+	er := &EnvelopeResult{
+		LiveDrawdown : core.Trunc2d(maxDD),
+		LiveDuration : duration,
+		DepthPerc    : core.Trunc2d(depthPerc   .Rank(-maxDD)),
+		DurationPerc : core.Trunc2d(durationPerc.Rank(float64(duration))),
+		DepthLimit   : core.Trunc2d(-depthPerc  .Get(percentile)),
+		DurationLimit: int(durationPerc.Get(percentile)),
+	}
+
+	er.Exceeded = er.DepthPerc > percentile || er.DurationPerc > percentile
+
+	return er
+}
+
+//=============================================================================
+//--- Stores the result of the check and flags the system for checking when the
+//--- envelope is exceeded. A nil result clears the envelope. Only the envelope
+//--- fields and the suggested action are changed
+
+func UpdateEnvelope(ts *db.TradingSystem, er *EnvelopeResult) {
+	if er == nil {
+		er = &EnvelopeResult{}
+	}
+
+	//--- Remove our suggestion, unless the status requires a check for other reasons
+
+	if ts.EnvelopeExceeded && !er.Exceeded && ts.SuggestedAction == db.TsActionCheck {
+		if ts.Status != db.TsStatusBroken && ts.Status != db.TsStatusDegraded {
+			ts.SuggestedAction = db.TsActionNone
+		}
+	}
+
+	ts.EnvelopeExceeded  = er.Exceeded
+	ts.EnvelopeDepthPerc = er.DepthPerc
+	ts.EnvelopeDurPerc   = er.DurationPerc
+
+	UpdateEnvelopeAction(ts)
+}
+
+//=============================================================================
+//--- The suggested action is recalculated each time new trades arrive, so the
+//--- check requested by an exceeded envelope must be set again. A suggestion
+//--- coming from the trading filter is never replaced
+
+func UpdateEnvelopeAction(ts *db.TradingSystem) {
+	if ts.Running && ts.EnvelopeExceeded && ts.SuggestedAction == db.TsActionNone {
+		ts.SuggestedAction = db.TsActionCheck
+	}
+}
+
+//=============================================================================
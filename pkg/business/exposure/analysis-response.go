@@ -0,0 +1,100 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+package exposure
+
+import (
+	"time"
+)
+
+//=============================================================================
+//--- Exposure after all entries and exits at Time. Symbols holds the net
+//--- contracts (long positive, short negative) of each symbol with an open position
+
+type Point struct {
+	Time           time.Time      `json:"time"`
+	OpenPositions  int            `json:"openPositions"`
+	LongContracts  int            `json:"longContracts"`
+	ShortContracts int            `json:"shortContracts"`
+	Margin         float64        `json:"margin"`
+	Symbols        map[string]int `json:"symbols"`
+}
+
+//=============================================================================
+
+type Position struct {
+	TradingSystemId uint      `json:"tradingSystemId"`
+	Name            string    `json:"name"`
+	Symbol          string    `json:"symbol"`
+	TradeType       string    `json:"tradeType"`
+	Contracts       int       `json:"contracts"`
+	Margin          float64   `json:"margin"`
+	EntryDate       time.Time `json:"entryDate"`
+}
+
+//=============================================================================
+
+type Peak struct {
+	Time          time.Time   `json:"time"`
+	Margin        float64     `json:"margin"`
+	OpenPositions int         `json:"openPositions"`
+	Positions     []*Position `json:"positions"`
+}
+
+//=============================================================================
+
+type SymbolSummary struct {
+	Symbol           string `json:"symbol"`
+	MaxNetLong       int    `json:"maxNetLong"`
+	MaxNetShort      int    `json:"maxNetShort"`
+	MaxLongSystems   int    `json:"maxLongSystems"`
+	MaxShortSystems  int    `json:"maxShortSystems"`
+}
+
+//=============================================================================
+//--- Interval where several systems are in the same direction on a symbol
+
+type Warning struct {
+	Symbol         string    `json:"symbol"`
+	TradeType      string    `json:"tradeType"`
+	From           time.Time `json:"from"`
+	To             time.Time `json:"to"`
+	MaxSystems     int       `json:"maxSystems"`
+	TradingSystems []uint    `json:"tradingSystems"`
+}
+
+//=============================================================================
+//--- Trades without an exit date are still open: they are not part of the
+//--- analysis and are only counted in OpenTrades
+
+type AnalysisResponse struct {
+	MinSameDirection int              `json:"minSameDirection"`
+	Trades           int              `json:"trades"`
+	OpenTrades       int              `json:"openTrades"`
+	Points           []*Point         `json:"points"`
+	Peak             *Peak            `json:"peak"`
+	Symbols          []*SymbolSummary `json:"symbols"`
+	Warnings         []*Warning       `json:"warnings"`
+}
+
+//=============================================================================
@@ -0,0 +1,360 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+package exposure
+
+import (
+	"sort"
+	"time"
+
+	"github.com/tradalia/portfolio-trader/pkg/core"
+	"github.com/tradalia/portfolio-trader/pkg/db"
+)
+
+//=============================================================================
+
+const DefaultMinSameDirection = 2
+
+//=============================================================================
+
+type event struct {
+	time  time.Time
+	entry bool
+	trade *db.Trade
+}
+
+//-----------------------------------------------------------------------------
+
+type openWarning struct {
+	warning *Warning
+	systems map[uint]bool
+}
+
+//=============================================================================
+//--- Positions are rebuilt from the entry and exit dates of the trades. At the
+//--- same time, exits are processed before entries. Warnings are sorted by
+//--- start time, symbol and trade type
+
+func GetExposureAnalysis(tsMap map[uint]*db.TradingSystem, pc *core.ProfitCalculator, trades *[]db.Trade, minSameDirection int) *AnalysisResponse {
+	if minSameDirection == 0 {
+		minSameDirection = DefaultMinSameDirection
+	}
+
+	res := &AnalysisResponse{
+		MinSameDirection: minSameDirection,
+		Points          : []*Point{},
+		Symbols         : []*SymbolSummary{},
+		Warnings        : []*Warning{},
+	}
+
+	events  := buildEvents(tsMap, trades, res)
+	open    := map[*db.Trade]bool{}
+	sumMap  := map[string]*SymbolSummary{}
+	warnMap := map[string]*openWarning{}
+
+	res.Trades = len(events) / 2
+
+	for i := 0; i < len(events); {
+		now := events[i].time
+
+		for ; i < len(events) && events[i].time.Equal(now); i++ {
+			if events[i].entry {
+				open[events[i].trade] = true
+			} else {
+				delete(open, events[i].trade)
+			}
+		}
+
+		point := buildPoint(tsMap, pc, open, now)
+		res.Points = append(res.Points, point)
+
+		if res.Peak == nil || point.Margin > res.Peak.Margin {
+			res.Peak = buildPeak(tsMap, pc, open, point)
+		}
+
+		updateSymbols (tsMap, open, sumMap)
+		updateWarnings(tsMap, open, now, minSameDirection, warnMap, res)
+	}
+
+	for _, sum := range sumMap {
+		res.Symbols = append(res.Symbols, sum)
+	}
+
+	sort.Slice(res.Symbols, func(i, j int) bool {
+		return res.Symbols[i].Symbol < res.Symbols[j].Symbol
+	})
+
+	sort.Slice(res.Warnings, func(i, j int) bool {
+		wi, wj := res.Warnings[i], res.Warnings[j]
+
+		if !wi.From.Equal(wj.From) {
+			return wi.From.Before(wj.From)
+		}
+
+		if wi.Symbol != wj.Symbol {
+			return wi.Symbol < wj.Symbol
+		}
+
+		return wi.TradeType < wj.TradeType
+	})
+
+	return res
+}
+
+//=============================================================================
+//===
+//=== Private functions
+//===
+//=============================================================================
+
+func buildEvents(tsMap map[uint]*db.TradingSystem, trades *[]db.Trade, res *AnalysisResponse) []*event {
+	var events []*event
+
+	for i := range *trades {
+		tr := &(*trades)[i]
+		if tr.EntryDate == nil {
+			continue
+		}
+
+		if _, ok := tsMap[tr.TradingSystemId]; !ok {
+			continue
+		}
+
+		if tr.ExitDate == nil {
+			res.OpenTrades++
+			continue
+		}
+
+		events = append(events, &event{ time: *tr.EntryDate, entry: true,  trade: tr })
+		events = append(events, &event{ time: *tr.ExitDate,  entry: false, trade: tr })
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].time.Equal(events[j].time) {
+			return events[i].time.Before(events[j].time)
+		}
+
+		return !events[i].entry && events[j].entry
+	})
+
+	return events
+}
+
+//=============================================================================
+
+func buildPoint(tsMap map[uint]*db.TradingSystem, pc *core.ProfitCalculator, open map[*db.Trade]bool, now time.Time) *Point {
+	p := &Point{
+		Time   : now,
+		Symbols: map[string]int{},
+	}
+
+	for tr := range open {
+		ts        := tsMap[tr.TradingSystemId]
+		contracts := getContracts(tr)
+
+		p.OpenPositions++
+		p.Margin += pc.Margin(ts.Id) * float64(contracts)
+
+		if tr.TradeType == db.TradeTypeLong {
+			p.LongContracts += contracts
+			p.Symbols[getSymbol(ts)] += contracts
+		} else {
+			p.ShortContracts += contracts
+			p.Symbols[getSymbol(ts)] -= contracts
+		}
+	}
+
+	return p
+}
+
+//=============================================================================
+
+func buildPeak(tsMap map[uint]*db.TradingSystem, pc *core.ProfitCalculator, open map[*db.Trade]bool, point *Point) *Peak {
+	peak := &Peak{
+		Time         : point.Time,
+		Margin       : point.Margin,
+		OpenPositions: point.OpenPositions,
+		Positions    : []*Position{},
+	}
+
+	for tr := range open {
+		ts        := tsMap[tr.TradingSystemId]
+		contracts := getContracts(tr)
+
+		peak.Positions = append(peak.Positions, &Position{
+			TradingSystemId: ts.Id,
+			Name           : ts.Name,
+			Symbol         : getSymbol(ts),
+			TradeType      : tr.TradeType,
+			Contracts      : contracts,
+			Margin         : pc.Margin(ts.Id) * float64(contracts),
+			EntryDate      : *tr.EntryDate,
+		})
+	}
+
+	sort.Slice(peak.Positions, func(i, j int) bool {
+		return peak.Positions[i].TradingSystemId < peak.Positions[j].TradingSystemId
+	})
+
+	return peak
+}
+
+//=============================================================================
+
+func updateSymbols(tsMap map[uint]*db.TradingSystem, open map[*db.Trade]bool, sumMap map[string]*SymbolSummary) {
+	netMap   := map[string]int{}
+	longMap  := map[string]map[uint]bool{}
+	shortMap := map[string]map[uint]bool{}
+
+	for tr := range open {
+		ts     := tsMap[tr.TradingSystemId]
+		symbol := getSymbol(ts)
+
+		if tr.TradeType == db.TradeTypeLong {
+			netMap[symbol] += getContracts(tr)
+			addSystem(longMap, symbol, ts.Id)
+		} else {
+			netMap[symbol] -= getContracts(tr)
+			addSystem(shortMap, symbol, ts.Id)
+		}
+	}
+
+	for symbol, net := range netMap {
+		sum, ok := sumMap[symbol]
+		if !ok {
+			sum = &SymbolSummary{ Symbol: symbol }
+			sumMap[symbol] = sum
+		}
+
+		sum.MaxNetLong      = max(sum.MaxNetLong,       net)
+		sum.MaxNetShort     = max(sum.MaxNetShort,     -net)
+		sum.MaxLongSystems  = max(sum.MaxLongSystems,  len(longMap [symbol]))
+		sum.MaxShortSystems = max(sum.MaxShortSystems, len(shortMap[symbol]))
+	}
+}
+
+//=============================================================================
+//--- A warning starts when the number of systems in the same direction on a
+//--- symbol reaches the minimum and ends when it goes below
+
+func updateWarnings(tsMap map[uint]*db.TradingSystem, open map[*db.Trade]bool, now time.Time, minSystems int, warnMap map[string]*openWarning, res *AnalysisResponse) {
+	dirMap := map[string]map[uint]bool{}
+	info   := map[string][2]string{}
+
+	for tr := range open {
+		symbol := getSymbol(tsMap[tr.TradingSystemId])
+		key    := symbol +"|"+ tr.TradeType
+
+		addSystem(dirMap, key, tr.TradingSystemId)
+		info[key] = [2]string{ symbol, tr.TradeType }
+	}
+
+	//--- Close warnings
+
+	for key, ow := range warnMap {
+		if len(dirMap[key]) < minSystems {
+			ow.warning.To = now
+			ow.warning.TradingSystems = toSortedIds(ow.systems)
+			res.Warnings = append(res.Warnings, ow.warning)
+			delete(warnMap, key)
+		}
+	}
+
+	//--- Open or update warnings
+
+	for key, systems := range dirMap {
+		if len(systems) < minSystems {
+			continue
+		}
+
+		ow, ok := warnMap[key]
+		if !ok {
+			ow = &openWarning{
+				warning: &Warning{
+					Symbol   : info[key][0],
+					TradeType: info[key][1],
+					From     : now,
+				},
+				systems: map[uint]bool{},
+			}
+			warnMap[key] = ow
+		}
+
+		for id := range systems {
+			ow.systems[id] = true
+		}
+
+		ow.warning.MaxSystems = max(ow.warning.MaxSystems, len(systems))
+	}
+}
+
+//=============================================================================
+
+func addSystem(m map[string]map[uint]bool, key string, id uint) {
+	set, ok := m[key]
+	if !ok {
+		set = map[uint]bool{}
+		m[key] = set
+	}
+
+	set[id] = true
+}
+
+//=============================================================================
+
+func toSortedIds(set map[uint]bool) []uint {
+	ids := []uint{}
+
+	for id := range set {
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+
+	return ids
+}
+
+//=============================================================================
+//--- The broker symbol is used if available, as it is the traded instrument
+
+func getSymbol(ts *db.TradingSystem) string {
+	if ts.BrokerSymbol != "" {
+		return ts.BrokerSymbol
+	}
+
+	return ts.DataSymbol
+}
+
+//=============================================================================
+
+func getContracts(tr *db.Trade) int {
+	if tr.Contracts <= 0 {
+		return 1
+	}
+
+	return tr.Contracts
+}
+
+//=============================================================================
@@ -0,0 +1,147 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+
+
+package exposure
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/tradalia/portfolio-trader/pkg/core"
+	"github.com/tradalia/portfolio-trader/pkg/db"
+	"github.com/tradalia/portfolio-trader/pkg/db/dbtest"
+)
+
+//=============================================================================
+
+func TestGetExposureAnalysis(t *testing.T) {
+	tsMap := map[uint]*db.TradingSystem{
+		1: { Id: 1, Name: "ts1", BrokerSymbol: "ES", MarginValue: 1000 },
+		2: { Id: 2, Name: "ts2", BrokerSymbol: "ES", MarginValue: 2000 },
+		3: { Id: 3, Name: "ts3", DataSymbol  : "NQ", MarginValue:  500 },
+	}
+
+	//--- At 14:00 the exit of trade 1 must be processed before the entry of trade 3.
+	//--- Trades of unknown systems are ignored
+
+	trades := []db.Trade{
+		dbtest.NewTrade(1, db.TradeTypeLong,  dbtest.Time(2, 10), dbtest.Time(2, 14), 0, 1),
+		dbtest.NewTrade(2, db.TradeTypeLong,  dbtest.Time(2, 12), dbtest.Time(2, 16), 0, 2),
+		dbtest.NewTrade(3, db.TradeTypeShort, dbtest.Time(2, 14), dbtest.Time(2, 15), 0, 0),
+		dbtest.NewTrade(9, db.TradeTypeLong,  dbtest.Time(2, 11), dbtest.Time(2, 13), 0, 1),
+	}
+
+	res := GetExposureAnalysis(tsMap, core.NewProfitCalculator(tsMap), &trades, 0)
+
+	if res.Trades != 3 || res.MinSameDirection != DefaultMinSameDirection {
+		t.Errorf("Bad response: Expected 3 trades and min %v and got %v, %v", DefaultMinSameDirection, res.Trades, res.MinSameDirection)
+	}
+
+	margins := []float64{}
+	for _, p := range res.Points {
+		margins = append(margins, p.Margin)
+	}
+
+	if expected := []float64{ 1000, 5000, 4500, 4000, 0 }; !reflect.DeepEqual(margins, expected) {
+		t.Errorf("Bad margins: Expected %v and got %v", expected, margins)
+	}
+
+	if p := res.Points[2]; p.OpenPositions != 2 || p.LongContracts != 2 || p.ShortContracts != 1 || p.Symbols["ES"] != 2 || p.Symbols["NQ"] != -1 {
+		t.Errorf("Bad point at 14:00: Got %+v", p)
+	}
+
+	if res.Peak == nil || res.Peak.Margin != 5000 || len(res.Peak.Positions) != 2 || res.Peak.Positions[0].TradingSystemId != 1 {
+		t.Errorf("Bad peak: Expected margin 5000 with systems 1 and 2 and got %+v", res.Peak)
+	}
+
+	symbols := []SymbolSummary{
+		{ Symbol: "ES", MaxNetLong: 3, MaxLongSystems: 2 },
+		{ Symbol: "NQ", MaxNetShort: 1, MaxShortSystems: 1 },
+	}
+
+	if len(res.Symbols) != len(symbols) {
+		t.Fatalf("Bad symbols: Expected %v and got %v", len(symbols), len(res.Symbols))
+	}
+
+	for i, sum := range res.Symbols {
+		if *sum != symbols[i] {
+			t.Errorf("Bad symbol summary: Expected %+v and got %+v", symbols[i], *sum)
+		}
+	}
+
+	if len(res.Warnings) != 1 {
+		t.Fatalf("Bad warnings: Expected 1 and got %v", len(res.Warnings))
+	}
+
+	w := res.Warnings[0]
+	if w.Symbol != "ES" || w.TradeType != db.TradeTypeLong || w.From.Hour() != 12 || w.To.Hour() != 14 || w.MaxSystems != 2 || !reflect.DeepEqual(w.TradingSystems, []uint{ 1, 2 }) {
+		t.Errorf("Bad warning: Expected ES long from 12 to 14 with systems [1 2] and got %+v", w)
+	}
+}
+
+//=============================================================================
+
+func TestGetExposureAnalysisWarnings(t *testing.T) {
+	tsMap := map[uint]*db.TradingSystem{
+		1: { Id: 1, BrokerSymbol: "ES" },
+		2: { Id: 2, BrokerSymbol: "ES" },
+		3: { Id: 3, BrokerSymbol: "NQ" },
+		4: { Id: 4, BrokerSymbol: "NQ" },
+		5: { Id: 5, BrokerSymbol: "CL" },
+		6: { Id: 6, BrokerSymbol: "CL" },
+	}
+
+	//--- ES and NQ warnings start and end together, so they are sorted by symbol.
+	//--- The last trade is still open and must be excluded
+
+	trades := []db.Trade{
+		dbtest.NewTrade(1, db.TradeTypeLong,  dbtest.Time(3, 10), dbtest.Time(3, 14), 0, 1),
+		dbtest.NewTrade(2, db.TradeTypeLong,  dbtest.Time(3, 10), dbtest.Time(3, 14), 0, 1),
+		dbtest.NewTrade(3, db.TradeTypeShort, dbtest.Time(3, 10), dbtest.Time(3, 14), 0, 1),
+		dbtest.NewTrade(4, db.TradeTypeShort, dbtest.Time(3, 10), dbtest.Time(3, 14), 0, 1),
+		dbtest.NewTrade(5, db.TradeTypeLong,  dbtest.Time(2,  8), dbtest.Time(2,  9), 0, 1),
+		dbtest.NewTrade(6, db.TradeTypeLong,  dbtest.Time(2,  8), dbtest.Time(2,  9), 0, 1),
+		dbtest.NewTrade(1, db.TradeTypeLong,  dbtest.Time(4, 10), dbtest.Time(4, 10), 0, 1),
+	}
+
+	trades[6].ExitDate = nil
+
+	res := GetExposureAnalysis(tsMap, core.NewProfitCalculator(tsMap), &trades, 2)
+
+	if res.Trades != 6 || res.OpenTrades != 1 {
+		t.Errorf("Bad trades: Expected 6 closed and 1 open and got %v, %v", res.Trades, res.OpenTrades)
+	}
+
+	symbols := []string{}
+	for _, w := range res.Warnings {
+		symbols = append(symbols, w.Symbol)
+	}
+
+	if expected := []string{ "CL", "ES", "NQ" }; !reflect.DeepEqual(symbols, expected) {
+		t.Errorf("Bad warnings: Expected %v and got %v", expected, symbols)
+	}
+}
+
+//=============================================================================
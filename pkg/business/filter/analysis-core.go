@@ -57,6 +57,37 @@ func CalcActivation(ts *db.TradingSystem, filter *db.TradingFilter, list []db.Tr
 	return a.IsLastActive()
 }
 
+//=============================================================================
+//--- Returns the trades actually taken with the filter. As in the analysis, the
+//--- first trade is always taken and trade i depends on the activation at i-1
+
+func FilterTrades(ts *db.TradingSystem, filter *db.TradingFilter, list *[]db.Trade) *[]db.Trade {
+	var res []db.Trade
+
+	if len(*list) == 0 {
+		return &res
+	}
+
+	e := &Equities{}
+
+	calcUnfilteredEquityAndProfit(e, ts, list)
+
+	if filter.EquAvgEnabled {
+		e.Average = calcAverageEquity(e.Time, e.UnfilteredEquity, filter.EquAvgLen)
+	}
+
+	a := calcActivations(e, filter)
+	calcFilterActivation(e, a, filter)
+
+	for i, t := range *list {
+		if i == 0 || e.FilterActivation[i-1] != 0 {
+			res = append(res, t)
+		}
+	}
+
+	return &res
+}
+
 //=============================================================================
 //===
 //=== AnalysisResponse building
@@ -0,0 +1,108 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+
+
+package business
+
+import (
+	"time"
+
+	"github.com/tradalia/core/auth"
+	"github.com/tradalia/portfolio-trader/pkg/business/grouping"
+	"github.com/tradalia/portfolio-trader/pkg/core"
+	"github.com/tradalia/portfolio-trader/pkg/db"
+	"gorm.io/gorm"
+)
+
+//=============================================================================
+
+func RunGroupAnalysis(tx *gorm.DB, c *auth.Context, par *grouping.AnalysisRequest) (*grouping.AnalysisResponse, error) {
+	err := par.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	//--- Get trading systems
+
+	tsMap, err := getGroupTradingSystems(tx, c, par.PortfolioId)
+	if err != nil {
+		return nil, err
+	}
+
+	fromTime, toTime, err := calcPerformancePeriod(par.DaysBack, par.FromDate, par.ToDate, time.UTC)
+	if err != nil {
+		c.Log.Error("RunGroupAnalysis: Bad fromDate or toDate", "fromDate", par.FromDate, "toDate", par.ToDate, "error", err)
+		return nil, err
+	}
+
+	trades := &[]db.Trade{}
+	tsIds  := calcIdsArrayFromSourceIds(tsMap)
+
+	if len(tsIds) > 0 {
+		trades, err = db.FindTradesByTsIdsFromTime(tx, tsIds, fromTime, toTime)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	owner, err := getTradingSystemsOwner(c, tsMap)
+	if err != nil {
+		return nil, err
+	}
+
+	pc, err := core.NewUserProfitCalculator(tx, owner, tsMap)
+	if err != nil {
+		return nil, err
+	}
+
+	return grouping.GetGroupAnalysis(tsMap, pc, trades, par.GroupBy), nil
+}
+
+//=============================================================================
+//===
+//=== Private methods
+//===
+//=============================================================================
+
+func getGroupTradingSystems(tx *gorm.DB, c *auth.Context, portfolioId uint) (map[uint]*db.TradingSystem, error) {
+	if portfolioId != 0 {
+		_, tsMap, err := getPortfolioTradingSystems(tx, c, portfolioId)
+		return tsMap, err
+	}
+
+	list, err := db.GetTradingSystemsByUser(tx, c.Session.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	tsMap := map[uint]*db.TradingSystem{}
+	for i := range *list {
+		ts := &(*list)[i]
+		tsMap[ts.Id] = ts
+	}
+
+	return tsMap, nil
+}
+
+//=============================================================================
@@ -0,0 +1,69 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+
+
+package grouping
+
+import (
+	"time"
+)
+
+//=============================================================================
+
+type TradingSystemInfo struct {
+	Id   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+//=============================================================================
+//--- Systems without a value for the dimension are grouped under an empty key.
+//--- Equity and drawdown are built on the net profits of the trades, sorted by
+//--- exit date
+
+type Group struct {
+	Key            string               `json:"key"`
+	TradingSystems []*TradingSystemInfo `json:"tradingSystems"`
+	Trades         int                  `json:"trades"`
+	GrossProfit    float64              `json:"grossProfit"`
+	NetProfit      float64              `json:"netProfit"`
+	AverageTrade   float64              `json:"averageTrade"`
+	WinPerc        float64              `json:"winPerc"`
+	ProfitFactor   float64              `json:"profitFactor"`
+	MaxDrawdown    float64              `json:"maxDrawdown"`
+	Time           *[]time.Time         `json:"time"`
+	NetEquity      *[]float64           `json:"netEquity"`
+	NetDrawdown    *[]float64           `json:"netDrawdown"`
+}
+
+//=============================================================================
+//--- A trading system with many tags belongs to many groups. Currency is empty
+//--- if values are in the currency of each trading system
+
+type AnalysisResponse struct {
+	GroupBy  string   `json:"groupBy"`
+	Currency string   `json:"currency"`
+	Groups   []*Group `json:"groups"`
+}
+
+//=============================================================================
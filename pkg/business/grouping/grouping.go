@@ -0,0 +1,209 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+
+
+package grouping
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tradalia/portfolio-trader/pkg/core"
+	"github.com/tradalia/portfolio-trader/pkg/db"
+)
+
+//=============================================================================
+
+func GetGroupAnalysis(tsMap map[uint]*db.TradingSystem, pc *core.ProfitCalculator, trades *[]db.Trade, groupBy string) *AnalysisResponse {
+	res := &AnalysisResponse{
+		GroupBy : groupBy,
+		Currency: pc.Currency(),
+		Groups  : []*Group{},
+	}
+
+	//--- Assign trading systems to groups
+
+	groupMap := map[string]*Group{}
+	tsKeys   := map[uint][]string{}
+
+	for _, ts := range tsMap {
+		keys := getKeys(ts, groupBy)
+		tsKeys[ts.Id] = keys
+
+		for _, key := range keys {
+			g, ok := groupMap[key]
+			if !ok {
+				g = newGroup(key)
+				groupMap[key] = g
+			}
+
+			g.TradingSystems = append(g.TradingSystems, &TradingSystemInfo{
+				Id  : ts.Id,
+				Name: ts.Name,
+			})
+		}
+	}
+
+	//--- Trades are already sorted by exit date
+
+	profits := map[string]*[]float64{}
+	wins    := map[string]float64{}
+	losses  := map[string]float64{}
+
+	for _, tr := range *pc.ConvertTrades(trades) {
+		net := pc.NetProfit(&tr)
+
+		for _, key := range tsKeys[tr.TradingSystemId] {
+			g := groupMap[key]
+			g.Trades++
+			g.GrossProfit += pc.GrossProfit(&tr)
+			g.NetProfit   += net
+			*g.Time = append(*g.Time, *tr.ExitDate)
+
+			list, ok := profits[key]
+			if !ok {
+				list = &[]float64{}
+				profits[key] = list
+			}
+
+			*list = append(*list, net)
+
+			if net > 0 {
+				g.WinPerc++
+				wins[key] += net
+			} else {
+				losses[key] -= net
+			}
+		}
+	}
+
+	//--- Statistics
+
+	for key, g := range groupMap {
+		if list, ok := profits[key]; ok {
+			g.NetEquity = core.BuildEquity(list)
+			g.NetDrawdown, g.MaxDrawdown = core.BuildDrawDown(g.NetEquity)
+		}
+
+		if g.Trades > 0 {
+			g.AverageTrade = core.Trunc2d(g.NetProfit / float64(g.Trades))
+			g.WinPerc      = core.Trunc2d(g.WinPerc   / float64(g.Trades) * 100)
+		}
+
+		if losses[key] > 0 {
+			g.ProfitFactor = core.Trunc2d(wins[key] / losses[key])
+		}
+
+		g.GrossProfit = core.Trunc2d(g.GrossProfit)
+		g.NetProfit   = core.Trunc2d(g.NetProfit)
+		g.MaxDrawdown = core.Trunc2d(g.MaxDrawdown)
+
+		sort.Slice(g.TradingSystems, func(i, j int) bool {
+			return g.TradingSystems[i].Id < g.TradingSystems[j].Id
+		})
+
+		res.Groups = append(res.Groups, g)
+	}
+
+	//--- The group without a value goes last
+
+	sort.Slice(res.Groups, func(i, j int) bool {
+		ki := res.Groups[i].Key
+		kj := res.Groups[j].Key
+
+		if ki == "" || kj == "" {
+			return kj == "" && ki != ""
+		}
+
+		return ki < kj
+	})
+
+	return res
+}
+
+//=============================================================================
+//===
+//=== Private functions
+//===
+//=============================================================================
+
+func newGroup(key string) *Group {
+	return &Group{
+		Key           : key,
+		TradingSystems: []*TradingSystemInfo{},
+		Time          : &[]time.Time{},
+		NetEquity     : &[]float64{},
+		NetDrawdown   : &[]float64{},
+	}
+}
+
+//=============================================================================
+
+func getKeys(ts *db.TradingSystem, groupBy string) []string {
+	switch groupBy {
+		case GroupByMarketType:
+			return []string{ ts.MarketType }
+		case GroupByStrategyType:
+			return []string{ ts.StrategyType }
+		case GroupByTimeframe:
+			return []string{ strconv.Itoa(ts.Timeframe) }
+		case GroupByEngineCode:
+			return []string{ ts.EngineCode }
+		case GroupByAgentProfile:
+			if ts.AgentProfileId == nil {
+				return []string{ "" }
+			}
+
+			return []string{ strconv.FormatUint(uint64(*ts.AgentProfileId), 10) }
+		case GroupByTag:
+			return getTags(ts.Tags)
+	}
+
+	return []string{ "" }
+}
+
+//=============================================================================
+
+func getTags(tags string) []string {
+	var list []string
+	seen := map[string]bool{}
+
+	for _, tag := range strings.Split(tags, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag != "" && !seen[tag] {
+			seen[tag] = true
+			list  = append(list, tag)
+		}
+	}
+
+	if len(list) == 0 {
+		return []string{ "" }
+	}
+
+	return list
+}
+
+//=============================================================================
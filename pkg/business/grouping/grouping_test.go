@@ -0,0 +1,152 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+
+
+package grouping
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/tradalia/portfolio-trader/pkg/core"
+	"github.com/tradalia/portfolio-trader/pkg/db"
+	"github.com/tradalia/portfolio-trader/pkg/db/dbtest"
+)
+
+//=============================================================================
+
+func TestGetTags(t *testing.T) {
+	cases := []struct {
+		tags     string
+		expected []string
+	}{
+		{ "",                  []string{ "" } },
+		{ " , ,",              []string{ "" } },
+		{ "trend",             []string{ "trend" } },
+		{ "trend, swing ,",    []string{ "trend", "swing" } },
+		{ "trend,swing,trend", []string{ "trend", "swing" } },
+	}
+
+	for _, c := range cases {
+		if tags := getTags(c.tags); !reflect.DeepEqual(tags, c.expected) {
+			t.Errorf("Bad tags for '%v': Expected %v and got %v", c.tags, c.expected, tags)
+		}
+	}
+}
+
+//=============================================================================
+
+func TestGetKeys(t *testing.T) {
+	profileId := uint(7)
+
+	ts := &db.TradingSystem{
+		MarketType    : "FX",
+		StrategyType  : "TF",
+		Timeframe     : 60,
+		EngineCode    : "ts",
+		AgentProfileId: &profileId,
+		Tags          : "a,b",
+	}
+
+	cases := []struct {
+		groupBy  string
+		expected []string
+	}{
+		{ GroupByMarketType,   []string{ "FX" } },
+		{ GroupByStrategyType, []string{ "TF" } },
+		{ GroupByTimeframe,    []string{ "60" } },
+		{ GroupByEngineCode,   []string{ "ts" } },
+		{ GroupByAgentProfile, []string{ "7" } },
+		{ GroupByTag,          []string{ "a", "b" } },
+		{ "unknown",           []string{ "" } },
+	}
+
+	for _, c := range cases {
+		if keys := getKeys(ts, c.groupBy); !reflect.DeepEqual(keys, c.expected) {
+			t.Errorf("Bad keys for %v: Expected %v and got %v", c.groupBy, c.expected, keys)
+		}
+	}
+
+	ts.AgentProfileId = nil
+
+	if keys := getKeys(ts, GroupByAgentProfile); !reflect.DeepEqual(keys, []string{ "" }) {
+		t.Errorf("Bad keys without agent profile: Expected [\"\"] and got %v", keys)
+	}
+}
+
+//=============================================================================
+
+func TestGetGroupAnalysis(t *testing.T) {
+	tsMap := map[uint]*db.TradingSystem{
+		1: { Id: 1, Name: "ts1", Tags: "a,b" },
+		2: { Id: 2, Name: "ts2", Tags: "b"   },
+		3: { Id: 3, Name: "ts3", Tags: ""    },
+	}
+
+	trades := &[]db.Trade{
+		dbtest.NewTrade(1, db.TradeTypeLong, dbtest.Time(1, 10), dbtest.Time(1, 15),  100, 1),
+		dbtest.NewTrade(2, db.TradeTypeLong, dbtest.Time(2, 10), dbtest.Time(2, 15),   30, 1),
+		dbtest.NewTrade(1, db.TradeTypeLong, dbtest.Time(3, 10), dbtest.Time(3, 15),  -50, 1),
+		dbtest.NewTrade(3, db.TradeTypeLong, dbtest.Time(4, 10), dbtest.Time(4, 15),   10, 1),
+	}
+
+	res := GetGroupAnalysis(tsMap, core.NewProfitCalculator(tsMap), trades, GroupByTag)
+
+	expected := []struct {
+		key          string
+		systems      int
+		trades       int
+		netProfit    float64
+		winPerc      float64
+		profitFactor float64
+		maxDrawdown  float64
+	}{
+		{ "a", 1, 2, 50,  50,    2,   -50 },
+		{ "b", 2, 3, 80,  66.66, 2.6, -50 },
+		{ "",  1, 1, 10,  100,   0,     0 },
+	}
+
+	if len(res.Groups) != len(expected) {
+		t.Fatalf("Bad number of groups: Expected %v and got %v", len(expected), len(res.Groups))
+	}
+
+	for i, e := range expected {
+		g := res.Groups[i]
+
+		if g.Key != e.key || len(g.TradingSystems) != e.systems || g.Trades != e.trades {
+			t.Errorf("Bad group %v: Expected '%v' with %v systems and %v trades and got '%v' with %v systems and %v trades", i, e.key, e.systems, e.trades, g.Key, len(g.TradingSystems), g.Trades)
+		}
+
+		if g.NetProfit != e.netProfit || g.WinPerc != e.winPerc || g.ProfitFactor != e.profitFactor || g.MaxDrawdown != e.maxDrawdown {
+			t.Errorf("Bad statistics for group '%v': Expected %v, %v, %v, %v and got %v, %v, %v, %v", e.key,
+				e.netProfit, e.winPerc, e.profitFactor, e.maxDrawdown, g.NetProfit, g.WinPerc, g.ProfitFactor, g.MaxDrawdown)
+		}
+
+		if len(*g.Time) != e.trades || len(*g.NetEquity) != e.trades {
+			t.Errorf("Bad series length for group '%v': Expected %v and got %v, %v", e.key, e.trades, len(*g.Time), len(*g.NetEquity))
+		}
+	}
+}
+
+//=============================================================================
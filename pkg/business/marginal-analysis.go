@@ -0,0 +1,95 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+package business
+
+import (
+	"time"
+
+	"github.com/tradalia/core/auth"
+	"github.com/tradalia/core/req"
+	"github.com/tradalia/portfolio-trader/pkg/business/marginal"
+	"github.com/tradalia/portfolio-trader/pkg/core"
+	"github.com/tradalia/portfolio-trader/pkg/db"
+	"gorm.io/gorm"
+)
+
+//=============================================================================
+
+func RunMarginalAnalysis(tx *gorm.DB, c *auth.Context, par *marginal.AnalysisRequest) (*marginal.AnalysisResponse, error) {
+
+	//--- Get portfolio systems and candidates
+
+	_, portMap, err := getPortfolioTradingSystems(tx, c, par.PortfolioId)
+	if err != nil {
+		return nil, err
+	}
+
+	candMap, err := resolveTradingSystems(tx, c, par.CandidateIds, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	for id := range candMap {
+		if _, ok := portMap[id]; ok {
+			return nil, req.NewBadRequestError("Candidate already in the portfolio: %v", id)
+		}
+	}
+
+	//--- Daily returns are stored by UTC day
+
+	fromTime, toTime, err := calcPerformancePeriod(par.DaysBack, par.FromDate, par.ToDate, time.UTC)
+	if err != nil {
+		c.Log.Error("RunMarginalAnalysis: Bad fromDate or toDate", "fromDate", par.FromDate, "toDate", par.ToDate, "error", err)
+		return nil, err
+	}
+
+	tsIds := append(calcIdsArrayFromSourceIds(portMap), calcIdsArrayFromSourceIds(candMap)...)
+
+	returns, err := db.FindDailyReturnsByTsIdsFromTime(tx, tsIds, fromTime, toTime)
+	if err != nil {
+		return nil, err
+	}
+
+	tsMap := map[uint]*db.TradingSystem{}
+	for id, ts := range portMap {
+		tsMap[id] = ts
+	}
+	for id, ts := range candMap {
+		tsMap[id] = ts
+	}
+
+	owner, err := getTradingSystemsOwner(c, tsMap)
+	if err != nil {
+		return nil, err
+	}
+
+	pc, err := core.NewUserProfitCalculator(tx, owner, tsMap)
+	if err != nil {
+		return nil, err
+	}
+
+	return marginal.GetMarginalAnalysis(par.PortfolioId, portMap, candMap, pc, returns), nil
+}
+
+//=============================================================================
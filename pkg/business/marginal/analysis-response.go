@@ -0,0 +1,72 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+package marginal
+
+//=============================================================================
+
+type Month struct {
+	Month     string  `json:"month"`
+	NetProfit float64 `json:"netProfit"`
+}
+
+//=============================================================================
+//--- Drawdown duration is expressed in trading days
+
+type Metrics struct {
+	NetProfit        float64 `json:"netProfit"`
+	MaxDrawdown      float64 `json:"maxDrawdown"`
+	DrawdownDuration int     `json:"drawdownDuration"`
+	SharpeRatio      float64 `json:"sharpeRatio"`
+	WorstMonth       *Month  `json:"worstMonth"`
+}
+
+//=============================================================================
+//--- Correlation is calculated on the days where both the candidate and the
+//--- portfolio were trading. After holds the portfolio metrics when only this
+//--- candidate is added
+
+type Candidate struct {
+	Id          uint     `json:"id"`
+	Name        string   `json:"name"`
+	Correlation float64  `json:"correlation"`
+	CommonDays  int      `json:"commonDays"`
+	Standalone  *Metrics `json:"standalone"`
+	After       *Metrics `json:"after"`
+	Delta       *Metrics `json:"delta"`
+}
+
+//=============================================================================
+//--- After holds the portfolio metrics when all candidates are added
+
+type AnalysisResponse struct {
+	PortfolioId uint         `json:"portfolioId"`
+	FromDate    string       `json:"fromDate"`
+	ToDate      string       `json:"toDate"`
+	Before      *Metrics     `json:"before"`
+	After       *Metrics     `json:"after"`
+	Delta       *Metrics     `json:"delta"`
+	Candidates  []*Candidate `json:"candidates"`
+}
+
+//=============================================================================
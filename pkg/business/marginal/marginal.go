@@ -0,0 +1,210 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+package marginal
+
+import (
+	"fmt"
+
+	"github.com/tradalia/portfolio-trader/pkg/core"
+	"github.com/tradalia/portfolio-trader/pkg/core/stats"
+	"github.com/tradalia/portfolio-trader/pkg/db"
+)
+
+//=============================================================================
+
+const MinCommonDays = 20
+
+//=============================================================================
+
+//--- The profit calculator must include both portfolio systems and candidates
+
+func GetMarginalAnalysis(portfolioId uint, portMap, candMap map[uint]*db.TradingSystem, pc *core.ProfitCalculator, returns *[]db.DailyReturn) *AnalysisResponse {
+	tsMap := map[uint]*db.TradingSystem{}
+	for id, ts := range portMap {
+		tsMap[id] = ts
+	}
+	for id, ts := range candMap {
+		tsMap[id] = ts
+	}
+
+	ar  := core.BuildAlignedReturns(pc.ConvertDailyReturns(returns), pc)
+	res := &AnalysisResponse{
+		PortfolioId: portfolioId,
+		Candidates : []*Candidate{},
+	}
+
+	if len(ar.Days) == 0 {
+		return res
+	}
+
+	res.FromDate = ar.Days[0].String()
+	res.ToDate   = ar.Days[len(ar.Days) -1].String()
+
+	//--- Portfolio alone and with all candidates
+
+	portfolio, portFirst, portLast := sumReturns(ar, portMap)
+	combined,  _,         _        := sumReturns(ar, tsMap)
+
+	res.Before = calcMetrics(ar, portfolio)
+	res.After  = calcMetrics(ar, combined)
+	res.Delta  = calcDelta(res.Before, res.After)
+
+	//--- Each candidate alone
+
+	for _, id := range ar.TradingSystemIds() {
+		if _, ok := candMap[id]; !ok {
+			continue
+		}
+
+		cand  := ar.Returns[id]
+		after := make([]float64, len(cand))
+		for i := range cand {
+			after[i] = portfolio[i] + cand[i]
+		}
+
+		c := &Candidate{
+			Id        : id,
+			Name      : candMap[id].Name,
+			Standalone: calcMetrics(ar, cand),
+			After     : calcMetrics(ar, after),
+		}
+
+		c.Delta = calcDelta(res.Before, c.After)
+
+		from := max(portFirst, ar.First[id])
+		to   := min(portLast,  ar.Last [id])
+
+		if to - from + 1 >= MinCommonDays {
+			c.CommonDays  = to - from + 1
+			c.Correlation = core.Trunc2d(stats.Correlation(portfolio[from:to+1], cand[from:to+1]))
+		}
+
+		res.Candidates = append(res.Candidates, c)
+	}
+
+	return res
+}
+
+//=============================================================================
+//===
+//=== Private functions
+//===
+//=============================================================================
+//--- Returns the daily sum and the range of days where at least one system was trading
+
+func sumReturns(ar *core.AlignedReturns, tsMap map[uint]*db.TradingSystem) ([]float64, int, int) {
+	sum   := make([]float64, len(ar.Days))
+	first := len(ar.Days)
+	last  := -1
+
+	for id := range tsMap {
+		returns, ok := ar.Returns[id]
+		if !ok {
+			continue
+		}
+
+		for i, value := range returns {
+			sum[i] += value
+		}
+
+		first = min(first, ar.First[id])
+		last  = max(last,  ar.Last [id])
+	}
+
+	return sum, first, last
+}
+
+//=============================================================================
+
+func calcMetrics(ar *core.AlignedReturns, daily []float64) *Metrics {
+	equity := core.BuildEquity(&daily)
+	dd, maxDD := core.BuildDrawDown(equity)
+
+	m := &Metrics{
+		MaxDrawdown     : core.Trunc2d(maxDD),
+		DrawdownDuration: core.CalcMaxDrawDownDuration(dd),
+		WorstMonth      : calcWorstMonth(ar, daily),
+	}
+
+	if len(*equity) > 0 {
+		m.NetProfit = core.Trunc2d((*equity)[len(*equity) -1])
+	}
+
+	mean   := stats.Mean(daily)
+	stdDev := stats.StdDev(daily, mean)
+
+	m.SharpeRatio = core.Trunc2d(stats.AnnualizedSharpeRatio(mean, stdDev))
+
+	return m
+}
+
+//=============================================================================
+
+func calcWorstMonth(ar *core.AlignedReturns, daily []float64) *Month {
+	var worst *Month
+
+	for i := 0; i < len(daily); {
+		month := ar.Days[i].Year() * 100 + ar.Days[i].Month()
+		sum   := 0.0
+
+		for ; i < len(daily) && ar.Days[i].Year() * 100 + ar.Days[i].Month() == month; i++ {
+			sum += daily[i]
+		}
+
+		if worst == nil || sum < worst.NetProfit {
+			worst = &Month{
+				Month    : fmt.Sprintf("%d-%02d", month / 100, month % 100),
+				NetProfit: sum,
+			}
+		}
+	}
+
+	if worst != nil {
+		worst.NetProfit = core.Trunc2d(worst.NetProfit)
+	}
+
+	return worst
+}
+
+//=============================================================================
+
+func calcDelta(before, after *Metrics) *Metrics {
+	delta := &Metrics{
+		NetProfit       : core.Trunc2d(after.NetProfit   - before.NetProfit),
+		MaxDrawdown     : core.Trunc2d(after.MaxDrawdown - before.MaxDrawdown),
+		DrawdownDuration: after.DrawdownDuration - before.DrawdownDuration,
+		SharpeRatio     : core.Trunc2d(after.SharpeRatio - before.SharpeRatio),
+	}
+
+	if before.WorstMonth != nil && after.WorstMonth != nil {
+		delta.WorstMonth = &Month{
+			Month    : after.WorstMonth.Month,
+			NetProfit: core.Trunc2d(after.WorstMonth.NetProfit - before.WorstMonth.NetProfit),
+		}
+	}
+
+	return delta
+}
+
+//=============================================================================
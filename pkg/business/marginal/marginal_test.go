@@ -0,0 +1,114 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+
+
+package marginal
+
+import (
+	"testing"
+
+	"github.com/tradalia/core/datatype"
+	"github.com/tradalia/portfolio-trader/pkg/core"
+	"github.com/tradalia/portfolio-trader/pkg/db"
+)
+
+//=============================================================================
+
+func TestCalcWorstMonth(t *testing.T) {
+	ar := &core.AlignedReturns{
+		Days: []datatype.IntDate{ 20250130, 20250131, 20250203, 20250228, 20250303, 20260105 },
+	}
+
+	cases := []struct {
+		daily     []float64
+		month     string
+		netProfit float64
+	}{
+		{ []float64{ 10, -5,  -3, -4,  2, 1 }, "2025-02", -7 },
+		{ []float64{ -1, -1,   3,  0, -1, 5 }, "2025-01", -2 },
+		{ []float64{  1,  1,   1,  1,  1, 1 }, "2025-03",  1 },
+		{ []float64{  0,  0,   0,  0,  0, 0 }, "2025-01",  0 },
+	}
+
+	for _, c := range cases {
+		worst := calcWorstMonth(ar, c.daily)
+
+		if worst == nil || worst.Month != c.month || worst.NetProfit != c.netProfit {
+			t.Errorf("Bad worst month for %v: Expected %v (%v) and got %+v", c.daily, c.month, c.netProfit, worst)
+		}
+	}
+
+	if worst := calcWorstMonth(&core.AlignedReturns{}, []float64{}); worst != nil {
+		t.Errorf("Bad worst month: Expected nil and got %+v", worst)
+	}
+}
+
+//=============================================================================
+
+func TestSumReturns(t *testing.T) {
+	ar := &core.AlignedReturns{
+		Days   : []datatype.IntDate{ 20250101, 20250102, 20250103, 20250106 },
+		Returns: map[uint][]float64{
+			1: { 0, 5, -2, 0 },
+			2: { 0, 0,  3, 4 },
+			3: { 9, 9,  9, 9 },
+		},
+		First: map[uint]int{ 1: 1, 2: 2, 3: 0 },
+		Last : map[uint]int{ 1: 2, 2: 3, 3: 3 },
+	}
+
+	tsMap := map[uint]*db.TradingSystem{ 1: {}, 2: {}, 4: {} }
+
+	sum, first, last := sumReturns(ar, tsMap)
+	expected := []float64{ 0, 5, 1, 4 }
+
+	for i := range expected {
+		if sum[i] != expected[i] {
+			t.Errorf("Bad daily sum: Expected %v and got %v", expected, sum)
+			break
+		}
+	}
+
+	if first != 1 || last != 3 {
+		t.Errorf("Bad range: Expected [1,3] and got [%v,%v]", first, last)
+	}
+}
+
+//=============================================================================
+
+func TestCalcDelta(t *testing.T) {
+	before := &Metrics{ NetProfit: 100, MaxDrawdown: -50, DrawdownDuration: 10, SharpeRatio: 1.2, WorstMonth: &Month{ Month: "2025-01", NetProfit: -30 } }
+	after  := &Metrics{ NetProfit: 130, MaxDrawdown: -40, DrawdownDuration: 12, SharpeRatio: 1.5, WorstMonth: &Month{ Month: "2025-02", NetProfit: -20 } }
+	delta  := calcDelta(before, after)
+
+	if delta.NetProfit != 30 || delta.MaxDrawdown != 10 || delta.DrawdownDuration != 2 || delta.SharpeRatio != 0.3 {
+		t.Errorf("Bad delta: Expected {30 10 2 0.3} and got %+v", delta)
+	}
+
+	if delta.WorstMonth == nil || delta.WorstMonth.Month != "2025-02" || delta.WorstMonth.NetProfit != 10 {
+		t.Errorf("Bad worst month delta: Expected 2025-02 (10) and got %+v", delta.WorstMonth)
+	}
+}
+
+//=============================================================================
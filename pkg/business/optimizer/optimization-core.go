@@ -0,0 +1,135 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+package optimizer
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/tradalia/portfolio-trader/pkg/core"
+	"github.com/tradalia/portfolio-trader/pkg/db"
+)
+
+//=============================================================================
+//--- One optimization per user, as candidates can span several portfolios
+
+var jobs = struct {
+	sync.RWMutex
+	m map[string]*OptimizationProcess
+}{m: make(map[string]*OptimizationProcess)}
+
+//=============================================================================
+//===
+//=== Init
+//===
+//=============================================================================
+
+func init() {
+	go periodicCleanup()
+}
+
+//=============================================================================
+//===
+//=== API methods
+//===
+//=============================================================================
+
+func StartOptimization(username string, tsMap map[uint]*db.TradingSystem, pc *core.ProfitCalculator, returns *[]db.DailyReturn, or *OptimizationRequest) {
+	jobs.Lock()
+	defer jobs.Unlock()
+
+	op, ok := jobs.m[username]
+	if ok {
+		slog.Error("Stopping a previous portfolio optimization process", "username", username)
+		op.Stop()
+		delete(jobs.m, username)
+	}
+
+	op = NewOptimizationProcess(username, tsMap, pc, returns, or)
+	op.Start()
+	jobs.m[username] = op
+}
+
+//=============================================================================
+
+func StopOptimization(username string) error {
+	jobs.Lock()
+	defer jobs.Unlock()
+
+	op, ok := jobs.m[username]
+	if ok {
+		op.Stop()
+	}
+
+	return nil
+}
+
+//=============================================================================
+
+func GetOptimizationInfo(username string) *OptimizationInfo {
+	jobs.Lock()
+	defer jobs.Unlock()
+
+	op, ok := jobs.m[username]
+	if !ok {
+		return &OptimizationInfo{
+			Status: OptimStatusIdle,
+		}
+	}
+
+	return op.GetInfo()
+}
+
+//=============================================================================
+//===
+//=== Cleanup process
+//===
+//=============================================================================
+
+func periodicCleanup() {
+	for {
+		time.Sleep(time.Minute * 5)
+		purge()
+	}
+}
+
+//=============================================================================
+
+func purge() {
+	jobs.Lock()
+	defer jobs.Unlock()
+
+	for username, op := range jobs.m {
+		if op.info.isComplete() {
+			delta := time.Now().Sub(op.info.EndTime)
+			if delta.Minutes() >= 30 {
+				slog.Info("purge: Purging portfolio optimization entry for user", "username", username)
+				delete(jobs.m, username)
+			}
+		}
+	}
+}
+
+//=============================================================================
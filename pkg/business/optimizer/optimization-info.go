@@ -0,0 +1,198 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+package optimizer
+
+import (
+	"sync"
+	"time"
+
+	"github.com/tradalia/portfolio-trader/pkg/core"
+)
+
+//=============================================================================
+//===
+//=== Run
+//===
+//=============================================================================
+
+type Allocation struct {
+	TsId       uint    `json:"tsId"`
+	Name       string  `json:"name"`
+	Multiplier int     `json:"multiplier"`
+	Margin     float64 `json:"margin"`
+}
+
+//-----------------------------------------------------------------------------
+
+type Run struct {
+	Allocations  []*Allocation `json:"allocations"`
+	FitnessValue float64       `json:"fitnessValue"`
+	NetProfit    float64       `json:"netProfit"`
+	MaxDrawdown  float64       `json:"maxDrawdown"`
+	SharpeRatio  float64       `json:"sharpeRatio"`
+	Margin       float64       `json:"margin"`
+	Systems      int           `json:"systems"`
+	random       int
+}
+
+//=============================================================================
+//===
+//=== OptimizationInfo
+//===
+//=============================================================================
+
+const OptimStatusIdle     = "idle"
+const OptimStatusRunning  = "running"
+const OptimStatusComplete = "complete"
+
+type OptimizationInfo struct {
+	sync.RWMutex
+	CurrStep  uint
+	MaxSteps  uint
+	StartTime time.Time
+	EndTime   time.Time
+	Status    string
+	results   *core.SortedResults
+
+	Objective    string
+	Mode         string
+	MarginBudget float64
+	Candidates   int
+	Days         int
+	Seed         int64
+	BestValue    float64
+	hasBest      bool
+}
+
+//=============================================================================
+
+func NewOptimizationInfo(maxResultSize int, r *OptimizationRequest, candidates, days int, seed int64) *OptimizationInfo {
+	oi := &OptimizationInfo{}
+	oi.CurrStep     = 0
+	oi.MaxSteps     = uint(r.Restarts * r.Iterations)
+	oi.StartTime    = time.Now()
+	oi.Status       = OptimStatusRunning
+	oi.results      = core.NewSortedResults(maxResultSize, runComparator)
+	oi.Objective    = r.Objective
+	oi.Mode         = r.Mode
+	oi.MarginBudget = r.MarginBudget
+	oi.Candidates   = candidates
+	oi.Days         = days
+	oi.Seed         = seed
+
+	return oi
+}
+
+//=============================================================================
+//===
+//=== Public methods
+//===
+//=============================================================================
+
+func (oi *OptimizationInfo) GetRuns() []any {
+	oi.Lock()
+	defer oi.Unlock()
+
+	if oi.results != nil {
+		return oi.results.ToList()
+	}
+
+	return nil
+}
+
+//=============================================================================
+//===
+//=== Private methods
+//===
+//=============================================================================
+
+func (oi *OptimizationInfo) step() {
+	oi.Lock()
+	defer oi.Unlock()
+
+	oi.CurrStep++
+}
+
+//=============================================================================
+
+func (oi *OptimizationInfo) addResult(r *Run) {
+	oi.Lock()
+	defer oi.Unlock()
+
+	oi.results.Add(r)
+
+	if !oi.hasBest || oi.BestValue < r.FitnessValue {
+		oi.BestValue = r.FitnessValue
+		oi.hasBest   = true
+	}
+}
+
+//=============================================================================
+
+func (oi *OptimizationInfo) setComplete() {
+	oi.Lock()
+	defer oi.Unlock()
+
+	oi.EndTime = time.Now()
+	oi.Status  = OptimStatusComplete
+}
+
+//=============================================================================
+
+func (oi *OptimizationInfo) isComplete() bool {
+	oi.RLock()
+	defer oi.RUnlock()
+
+	return oi.Status == OptimStatusComplete
+}
+
+//=============================================================================
+//===
+//=== Run comparator
+//===
+//=== Notes:
+//===  - in reverse order: max to min
+//=============================================================================
+
+func runComparator(a any, b any) int {
+	r1 := a.(*Run)
+	r2 := b.(*Run)
+	v1 := r1.FitnessValue
+	v2 := r2.FitnessValue
+
+	if v1 < v2 { return +1 }
+	if v1 > v2 { return -1 }
+
+	if r1.random == r2.random {
+		return 0
+	}
+
+	if r1.random < r2.random {
+		return 1
+	}
+
+	return -1
+}
+
+//=============================================================================
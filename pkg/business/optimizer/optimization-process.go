@@ -0,0 +1,367 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+package optimizer
+
+import (
+	"log/slog"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/tradalia/portfolio-trader/pkg/core"
+	"github.com/tradalia/portfolio-trader/pkg/core/stats"
+	"github.com/tradalia/portfolio-trader/pkg/db"
+)
+
+//=============================================================================
+
+const MaxResultSize = 100
+
+//=============================================================================
+//===
+//=== OptimizationProcess
+//===
+//=============================================================================
+//--- Stochastic hill climbing with random restarts. Each climb starts from a
+//--- random feasible allocation and moves by changing one multiplier or by
+//--- swapping one unit between two systems. Moves are accepted only if they
+//--- improve the objective
+
+type OptimizationProcess struct {
+	username string
+	optReq   *OptimizationRequest
+	info     *OptimizationInfo
+	ids      []uint
+	names    []string
+	margins  []float64
+	returns  [][]float64
+	days     int
+	rng      *rand.Rand
+	seen     map[string]bool
+	stopping atomic.Bool
+}
+
+//-----------------------------------------------------------------------------
+
+type evaluation struct {
+	mult      []int
+	fitness   float64
+	netProfit float64
+	valid     bool
+}
+
+//=============================================================================
+
+func NewOptimizationProcess(username string, tsMap map[uint]*db.TradingSystem, pc *core.ProfitCalculator, returns *[]db.DailyReturn, or *OptimizationRequest) *OptimizationProcess {
+	ar := core.BuildAlignedReturns(pc.ConvertDailyReturns(returns), pc)
+
+	op := &OptimizationProcess{
+		username: username,
+		optReq  : or,
+		days    : len(ar.Days),
+		seen    : map[string]bool{},
+	}
+
+	for _, id := range ar.TradingSystemIds() {
+		ts := tsMap[id]
+		op.ids     = append(op.ids,     id)
+		op.names   = append(op.names,   ts.Name)
+		op.margins = append(op.margins, pc.Margin(id))
+		op.returns = append(op.returns, ar.Returns[id])
+	}
+
+	return op
+}
+
+//=============================================================================
+
+func (op *OptimizationProcess) Start() {
+	seed := op.optReq.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	op.rng  = rand.New(rand.NewSource(seed))
+	op.info = NewOptimizationInfo(MaxResultSize, op.optReq, len(op.ids), op.days, seed)
+
+	go op.optimize()
+}
+
+//=============================================================================
+
+func (op *OptimizationProcess) Stop() {
+	slog.Info("Stop: Stopping portfolio optimization process", "username", op.username)
+	op.stopping.Store(true)
+}
+
+//=============================================================================
+
+func (op *OptimizationProcess) GetInfo() *OptimizationInfo {
+	return op.info
+}
+
+//=============================================================================
+//===
+//=== Private methods
+//===
+//=============================================================================
+
+//--- GoRoutine
+
+func (op *OptimizationProcess) optimize() {
+	slog.Info("optimize: Started", "username", op.username, "candidates", len(op.ids), "objective", op.optReq.Objective)
+
+	for r := 0; r < op.optReq.Restarts && !op.stopping.Load(); r++ {
+		op.climb()
+	}
+
+	op.info.setComplete()
+	slog.Info("optimize: Complete", "username", op.username)
+}
+
+//=============================================================================
+
+func (op *OptimizationProcess) climb() {
+	curr := op.evaluate(op.randomAllocation())
+
+	for i := 0; i < op.optReq.Iterations && !op.stopping.Load(); i++ {
+		next := op.randomMove(curr.mult)
+
+		if next != nil {
+			eval := op.evaluate(next)
+
+			if op.isBetter(eval, curr) {
+				curr = eval
+			}
+		}
+
+		op.info.step()
+	}
+}
+
+//=============================================================================
+//--- While the current allocation is not valid (target return not reached),
+//--- moves that increase the net profit are accepted
+
+func (op *OptimizationProcess) isBetter(eval, curr *evaluation) bool {
+	if eval.valid {
+		return !curr.valid || eval.fitness > curr.fitness
+	}
+
+	return !curr.valid && eval.netProfit > curr.netProfit
+}
+
+//=============================================================================
+//--- Systems are added in random order, as long as the allocation is feasible
+
+func (op *OptimizationProcess) randomAllocation() []int {
+	mult  := make([]int, len(op.ids))
+	count := 1 + op.rng.Intn(len(op.ids))
+
+	for _, i := range op.rng.Perm(len(op.ids))[:count] {
+		mult[i] = 1 + op.rng.Intn(op.optReq.MaxMultiplier)
+
+		for mult[i] > 0 && !op.isFeasible(mult) {
+			mult[i]--
+		}
+	}
+
+	return mult
+}
+
+//=============================================================================
+//--- Returns nil if the move produces an unfeasible allocation
+
+func (op *OptimizationProcess) randomMove(curr []int) []int {
+	next := make([]int, len(curr))
+	copy(next, curr)
+
+	i := op.rng.Intn(len(next))
+
+	if op.rng.Intn(2) == 0 {
+		//--- Change one multiplier
+
+		if op.rng.Intn(2) == 0 {
+			next[i]++
+		} else {
+			next[i]--
+		}
+	} else {
+		//--- Swap one unit between two systems
+
+		j := op.rng.Intn(len(next))
+		if i == j {
+			return nil
+		}
+
+		next[i]--
+		next[j]++
+	}
+
+	if !op.isFeasible(next) {
+		return nil
+	}
+
+	return next
+}
+
+//=============================================================================
+
+func (op *OptimizationProcess) isFeasible(mult []int) bool {
+	margin  := 0.0
+	systems := 0
+
+	for i, m := range mult {
+		if m < 0 || m > op.optReq.MaxMultiplier {
+			return false
+		}
+
+		if m > 0 {
+			systems++
+			margin += float64(m) * op.margins[i]
+		}
+	}
+
+	if systems == 0 {
+		return false
+	}
+
+	if op.optReq.MaxSystems > 0 && systems > op.optReq.MaxSystems {
+		return false
+	}
+
+	return margin <= op.optReq.MarginBudget
+}
+
+//=============================================================================
+
+func (op *OptimizationProcess) evaluate(mult []int) *evaluation {
+	if !op.isFeasible(mult) {
+		return &evaluation{ mult: mult, netProfit: math.Inf(-1) }
+	}
+
+	run := op.createRun(mult)
+	fitness, valid := op.calcFitness(run)
+	run.FitnessValue = core.Trunc2d(fitness)
+
+	key := allocationKey(mult)
+	if valid && !op.seen[key] {
+		op.seen[key] = true
+		op.info.addResult(run)
+	}
+
+	return &evaluation{
+		mult     : mult,
+		fitness  : fitness,
+		netProfit: run.NetProfit,
+		valid    : valid,
+	}
+}
+
+//=============================================================================
+
+func (op *OptimizationProcess) createRun(mult []int) *Run {
+	daily := make([]float64, op.days)
+	run   := &Run{
+		Allocations: []*Allocation{},
+		random     : op.rng.Int(),
+	}
+
+	for i, m := range mult {
+		if m == 0 {
+			continue
+		}
+
+		for d, value := range op.returns[i] {
+			daily[d] += float64(m) * value
+		}
+
+		margin := float64(m) * op.margins[i]
+		run.Margin += margin
+		run.Systems++
+		run.Allocations = append(run.Allocations, &Allocation{
+			TsId      : op.ids[i],
+			Name      : op.names[i],
+			Multiplier: m,
+			Margin    : margin,
+		})
+	}
+
+	equity := core.BuildEquity(&daily)
+	_, maxDD := core.BuildDrawDown(equity)
+
+	if len(*equity) > 0 {
+		run.NetProfit = (*equity)[len(*equity) -1]
+	}
+
+	mean   := stats.Mean(daily)
+	stdDev := stats.StdDev(daily, mean)
+
+	run.SharpeRatio = core.Trunc2d(stats.AnnualizedSharpeRatio(mean, stdDev))
+	run.NetProfit   = core.Trunc2d(run.NetProfit)
+	run.MaxDrawdown = core.Trunc2d(maxDD)
+
+	return run
+}
+
+//=============================================================================
+//--- Higher is better. Runs of the minDrawdown objective that don't reach the
+//--- target return are not valid
+
+func (op *OptimizationProcess) calcFitness(r *Run) (float64, bool) {
+	switch op.optReq.Objective {
+		case ObjectiveProfitDrawdown:
+			if r.MaxDrawdown == 0 {
+				return r.NetProfit, true
+			}
+
+			return r.NetProfit / math.Abs(r.MaxDrawdown), true
+
+		case ObjectiveSharpe:
+			return r.SharpeRatio, true
+
+		case ObjectiveMinDrawdown:
+			return r.MaxDrawdown, r.NetProfit >= op.optReq.TargetReturn
+	}
+
+	return 0, false
+}
+
+//=============================================================================
+
+func allocationKey(mult []int) string {
+	sb := strings.Builder{}
+
+	for _, m := range mult {
+		sb.WriteString(strconv.Itoa(m))
+		sb.WriteByte(',')
+	}
+
+	return sb.String()
+}
+
+//=============================================================================
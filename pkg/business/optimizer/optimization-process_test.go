@@ -0,0 +1,141 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+
+
+package optimizer
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/tradalia/portfolio-trader/pkg/core"
+)
+
+//=============================================================================
+
+func TestIsFeasible(t *testing.T) {
+	op := newTestProcess(&OptimizationRequest{ MaxMultiplier: 2, MarginBudget: 5000, MaxSystems: 2 })
+
+	cases := []struct {
+		mult     []int
+		feasible bool
+	}{
+		{ []int{ 0, 0, 0 }, false },
+		{ []int{ 1, 0, 0 }, true  },
+		{ []int{ 2, 1, 0 }, true  },
+		{ []int{ 1, 1, 1 }, false },
+		{ []int{ 0, 0, 2 }, false },
+		{ []int{ 3, 0, 0 }, false },
+		{ []int{-1, 1, 0 }, false },
+	}
+
+	for _, c := range cases {
+		if op.isFeasible(c.mult) != c.feasible {
+			t.Errorf("Bad feasibility of %v: Expected %v", c.mult, c.feasible)
+		}
+	}
+}
+
+//=============================================================================
+
+func TestCreateRun(t *testing.T) {
+	op  := newTestProcess(&OptimizationRequest{ Objective: ObjectiveProfitDrawdown, MaxMultiplier: 2, MarginBudget: 10000 })
+	run := op.createRun([]int{ 1, 0, 2 })
+
+	//--- Daily: 10+10, -20-10, 0+20, 30-30, 0+10 => 20, -30, 20, 0, 10
+
+	if run.Systems != 2 || run.Margin != 9000 || run.NetProfit != 20 || run.MaxDrawdown != -30 {
+		t.Errorf("Bad run: Expected 2 systems, margin 9000, profit 20, drawdown -30 and got %+v", run)
+	}
+
+	fitness, valid := op.calcFitness(run)
+	if !valid || fitness != 20.0 / 30.0 {
+		t.Errorf("Bad fitness: Expected 0.66 and got %v (valid=%v)", fitness, valid)
+	}
+
+	op.optReq.Objective    = ObjectiveMinDrawdown
+	op.optReq.TargetReturn = 25
+
+	if _, valid = op.calcFitness(run); valid {
+		t.Errorf("Bad fitness: Expected not valid below the target return")
+	}
+}
+
+//=============================================================================
+
+func TestOptimize(t *testing.T) {
+	or := &OptimizationRequest{ Objective: ObjectiveProfitDrawdown, Mode: ModeSubset, MarginBudget: 5000 }
+	if err := or.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	op := newTestProcess(or)
+	op.optimize()
+
+	//--- Check against all feasible subsets
+
+	best := 0.0
+	for _, mult := range [][]int{ {1,0,0}, {0,1,0}, {0,0,1}, {1,1,0}, {1,0,1}, {0,1,1} } {
+		if op.isFeasible(mult) {
+			fitness, _ := op.calcFitness(op.createRun(mult))
+			best = max(best, fitness)
+		}
+	}
+
+	runs := op.info.GetRuns()
+	if len(runs) == 0 || runs[0].(*Run).FitnessValue != core.Trunc2d(best) {
+		t.Errorf("Bad optimization: Expected best fitness %v and got %v", best, runs)
+	}
+
+	if !op.info.isComplete() {
+		t.Errorf("Bad optimization: Expected to be complete")
+	}
+}
+
+//=============================================================================
+
+func newTestProcess(or *OptimizationRequest) *OptimizationProcess {
+	if or.Restarts == 0 {
+		or.Restarts   = 5
+		or.Iterations = 50
+	}
+
+	return &OptimizationProcess{
+		optReq : or,
+		info   : NewOptimizationInfo(MaxResultSize, or, 3, 5, 1),
+		ids    : []uint{ 1, 2, 3 },
+		names  : []string{ "ts1", "ts2", "ts3" },
+		margins: []float64{ 1000, 2000, 4000 },
+		returns: [][]float64{
+			{ 10, -20,  0,  30,  0 },
+			{  5,   5, -5,   5, -5 },
+			{  5,  -5, 10, -15,  5 },
+		},
+		days   : 5,
+		rng    : rand.New(rand.NewSource(1)),
+		seen   : map[string]bool{},
+	}
+}
+
+//=============================================================================
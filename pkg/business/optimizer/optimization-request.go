@@ -0,0 +1,114 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+package optimizer
+
+import (
+	"github.com/tradalia/core/req"
+)
+
+//=============================================================================
+//===
+//=== OptimizationRequest
+//===
+//=============================================================================
+
+const (
+	ObjectiveProfitDrawdown = "netProfit/maxDD"
+	ObjectiveSharpe         = "sharpe"
+	ObjectiveMinDrawdown    = "minDrawdown"
+)
+
+const (
+	ModeSubset      = "subset"
+	ModeMultipliers = "multipliers"
+)
+
+const (
+	DefaultRestarts   = 20
+	DefaultIterations = 500
+	MaxEvaluations    = 200000
+)
+
+//=============================================================================
+//--- Candidates can be provided with a list of ids or with a portfolio.
+//--- MarginBudget is the max sum of the margins (MarginValue * multiplier).
+//--- TargetReturn is the min net profit over the period, used only by the
+//--- minDrawdown objective. MaxSystems = 0 means no limit
+
+type OptimizationRequest struct {
+	TsIds         []uint  `json:"tsIds"`
+	PortfolioId   uint    `json:"portfolioId"`
+	DaysBack      int     `json:"daysBack"      binding:"min=0,max=10000"`
+	Objective     string  `json:"objective"`
+	Mode          string  `json:"mode"`
+	MaxMultiplier int     `json:"maxMultiplier" binding:"min=0,max=100"`
+	MarginBudget  float64 `json:"marginBudget"  binding:"min=0"`
+	MaxSystems    int     `json:"maxSystems"    binding:"min=0"`
+	TargetReturn  float64 `json:"targetReturn"`
+	Restarts      int     `json:"restarts"      binding:"min=0,max=1000"`
+	Iterations    int     `json:"iterations"    binding:"min=0,max=100000"`
+	Seed          int64   `json:"seed"`
+}
+
+//=============================================================================
+
+func (r *OptimizationRequest) Validate() error {
+	if  r.Objective != ObjectiveProfitDrawdown &&
+		r.Objective != ObjectiveSharpe         &&
+		r.Objective != ObjectiveMinDrawdown {
+		return req.NewBadRequestError("Invalid objective: %v", r.Objective)
+	}
+
+	if r.Mode == "" {
+		r.Mode = ModeSubset
+	}
+
+	if r.Mode != ModeSubset && r.Mode != ModeMultipliers {
+		return req.NewBadRequestError("Invalid mode: %v", r.Mode)
+	}
+
+	if r.Mode == ModeSubset || r.MaxMultiplier == 0 {
+		r.MaxMultiplier = 1
+	}
+
+	if r.MarginBudget <= 0 {
+		return req.NewBadRequestError("A margin budget is required")
+	}
+
+	if r.Restarts == 0 {
+		r.Restarts = DefaultRestarts
+	}
+
+	if r.Iterations == 0 {
+		r.Iterations = DefaultIterations
+	}
+
+	if r.Restarts * r.Iterations > MaxEvaluations {
+		return req.NewBadRequestError("Too many evaluations: max is %v", MaxEvaluations)
+	}
+
+	return nil
+}
+
+//=============================================================================
@@ -0,0 +1,83 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+package optimizer
+
+import (
+	"time"
+)
+
+//=============================================================================
+//===
+//=== OptimizationResponse
+//===
+//=============================================================================
+
+type OptimizationResponse struct {
+	CurrStep     uint      `json:"currStep"`
+	MaxSteps     uint      `json:"maxSteps"`
+	StartTime    time.Time `json:"startTime"`
+	EndTime      time.Time `json:"endTime"`
+	Status       string    `json:"status"`
+	Objective    string    `json:"objective"`
+	Mode         string    `json:"mode"`
+	MarginBudget float64   `json:"marginBudget"`
+	Candidates   int       `json:"candidates"`
+	Days         int       `json:"days"`
+	Seed         int64     `json:"seed"`
+	BestValue    float64   `json:"bestValue"`
+	Runs         []any     `json:"runs"`
+	Duration     int64     `json:"duration"`
+}
+
+//=============================================================================
+
+func NewOptimizationResponse(info *OptimizationInfo) *OptimizationResponse {
+	info.RLock()
+	or := &OptimizationResponse{}
+	or.CurrStep     = info.CurrStep
+	or.MaxSteps     = info.MaxSteps
+	or.StartTime    = info.StartTime
+	or.EndTime      = info.EndTime
+	or.Status       = info.Status
+	or.Objective    = info.Objective
+	or.Mode         = info.Mode
+	or.MarginBudget = info.MarginBudget
+	or.Candidates   = info.Candidates
+	or.Days         = info.Days
+	or.Seed         = info.Seed
+	or.BestValue    = info.BestValue
+	info.RUnlock()
+
+	or.Runs = info.GetRuns()
+
+	if or.Status == OptimStatusComplete {
+		or.Duration = int64(or.EndTime.Sub(or.StartTime).Seconds())
+	} else if or.Status == OptimStatusRunning {
+		or.Duration = int64(time.Now().Sub(or.StartTime).Seconds())
+	}
+
+	return or
+}
+
+//=============================================================================
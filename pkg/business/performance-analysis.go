@@ -33,6 +33,7 @@ import (
 	"github.com/tradalia/portfolio-trader/pkg/business/performance"
 	"github.com/tradalia/portfolio-trader/pkg/core"
 	"github.com/tradalia/portfolio-trader/pkg/db"
+	"github.com/tradalia/portfolio-trader/pkg/platform"
 	"gorm.io/gorm"
 )
 
@@ -72,11 +73,127 @@ func RunPerformanceAnalysis(tx *gorm.DB, c *auth.Context, tsId uint, req *perfor
 		return nil,err
 	}
 
-	res := performance.GetPerformanceAnalysis(ts, trades, returns)
+	bench, err := getBenchmark(c, ts, req)
+	if err != nil {
+		return nil, err
+	}
+
+	res := performance.GetPerformanceAnalysis(ts, trades, returns, req, loc, bench)
+
+	return res, nil
+}
+
+//=============================================================================
+
+func RunPortfolioPerformanceAnalysis(tx *gorm.DB, c *auth.Context, id uint, req *performance.AnalysisRequest) (*performance.AnalysisResponse, error) {
+
+	//--- Get portfolio and the trading systems of its subtree
+
+	p, tsMap, err := getPortfolioTradingSystems(tx, c, id)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := getPortfolioLocation(req.Timezone, tsMap)
+	if err != nil {
+		c.Log.Error("RunPortfolioPerformanceAnalysis: Bad timezone", "timezone", req.Timezone, "error", err)
+		return nil, err
+	}
+
+	fromTime, toTime, err := calcPerformancePeriod(req.DaysBack, req.FromDate, req.ToDate, loc)
+	if err != nil {
+		c.Log.Error("RunPortfolioPerformanceAnalysis: Bad fromDate or toDate", "fromDate", req.FromDate, "toDate", req.ToDate, "error", err)
+		return nil, err
+	}
+
+	tsIds  := calcIdsArrayFromSourceIds(tsMap)
+	trades := &[]db.Trade{}
+	returns:= &[]db.DailyReturn{}
+
+	if len(tsIds) > 0 {
+		trades, err = db.FindTradesByTsIdsFromTime(tx, tsIds, fromTime, toTime)
+		if err != nil {
+			return nil,err
+		}
+
+		returns, err = db.FindDailyReturnsByTsIdsFromTime(tx, tsIds, fromTime, toTime)
+		if err != nil {
+			return nil,err
+		}
+	}
+
+	shiftTradesTimezone(trades, loc)
+
+	pc, err := core.NewUserProfitCalculator(tx, p.Username, tsMap)
+	if err != nil {
+		return nil, err
+	}
+
+	bench, err := getPortfolioBenchmark(c, pc, req)
+	if err != nil {
+		return nil, err
+	}
+
+	res := performance.GetPortfolioPerformanceAnalysis(p, tsMap, pc, trades, returns, req, loc, bench)
 
 	return res, nil
 }
 
+//=============================================================================
+//--- Benchmark prices are needed only if requested
+
+func getBenchmark(c *auth.Context, ts *db.TradingSystem, par *performance.AnalysisRequest) (*platform.DataProductAnalysisResponse, error) {
+	if par.Benchmark == nil {
+		return nil, nil
+	}
+
+	if par.Capital <= 0 && ts.MarginValue <= 0 {
+		return nil, req.NewBadRequestError("Benchmark comparison requires a capital or the margin of the trading system")
+	}
+
+	return platform.AnalyzeDataProduct(c, performance.BenchmarkDataProductId(ts, par.Benchmark), 0)
+}
+
+//=============================================================================
+//--- On a portfolio the benchmark must be explicit, as there is no single data product
+
+func getPortfolioBenchmark(c *auth.Context, pc *core.ProfitCalculator, par *performance.AnalysisRequest) (*platform.DataProductAnalysisResponse, error) {
+	if par.Benchmark == nil {
+		return nil, nil
+	}
+
+	if par.Benchmark.DataProductId == 0 {
+		return nil, req.NewBadRequestError("Benchmark comparison on a portfolio requires a data product")
+	}
+
+	if par.Capital <= 0 && pc.MarginValue() <= 0 {
+		return nil, req.NewBadRequestError("Benchmark comparison requires a capital or the margin of the trading systems")
+	}
+
+	return platform.AnalyzeDataProduct(c, par.Benchmark.DataProductId, 0)
+}
+
+//=============================================================================
+//--- The exchange timezone can be used only if all trading systems share it
+
+func getPortfolioLocation(timezone string, tsMap map[uint]*db.TradingSystem) (*time.Location, error) {
+	if timezone != "exchange" {
+		return time.LoadLocation(timezone)
+	}
+
+	tsTimezone := ""
+
+	for _, ts := range tsMap {
+		if tsTimezone != "" && tsTimezone != ts.Timezone {
+			return nil, req.NewBadRequestError("Trading systems have different exchange timezones")
+		}
+
+		tsTimezone = ts.Timezone
+	}
+
+	return time.LoadLocation(tsTimezone)
+}
+
 //=============================================================================
 
 func calcPerformancePeriod(daysBack int, fromDate, toDate datatype.IntDate, loc *time.Location) (*time.Time, *time.Time, error) {
@@ -29,12 +29,24 @@ import (
 )
 
 //=============================================================================
+//--- If Seed is 0, the bootstrap seed is derived from the trades, so the same
+//--- trades always give the same confidence intervals
 
 type AnalysisRequest struct {
-	DaysBack int               `json:"daysBack" binding:"max=10000"`
-	Timezone string            `json:"timezone" binding:"required"`
-	FromDate datatype.IntDate  `json:"fromDate"`
-	ToDate   datatype.IntDate  `json:"toDate"`
+	DaysBack  int               `json:"daysBack"  binding:"max=10000"`
+	Timezone  string            `json:"timezone"  binding:"required"`
+	FromDate  datatype.IntDate  `json:"fromDate"`
+	ToDate    datatype.IntDate  `json:"toDate"`
+	Capital   float64           `json:"capital"   binding:"min=0"`
+	Seed      int64             `json:"seed"`
+	Benchmark *BenchmarkRequest `json:"benchmark"`
+}
+
+//=============================================================================
+//--- If DataProductId is 0, the data product of the trading system is used
+
+type BenchmarkRequest struct {
+	DataProductId uint `json:"dataProductId"`
 }
 
 //=============================================================================
@@ -25,6 +25,7 @@ THE SOFTWARE.
 package performance
 
 import (
+	"math"
 	"time"
 
 	"github.com/tradalia/core/datatype"
@@ -66,6 +67,7 @@ type Equities struct {
 type General struct {
 	FromDate datatype.IntDate  `json:"fromDate"`
 	ToDate   datatype.IntDate  `json:"toDate"`
+	Timezone string            `json:"timezone"`
 }
 
 //=============================================================================
@@ -177,10 +179,40 @@ type Distributions struct {
 
 //=============================================================================
 
+//--- A bucket is significant when its mean net trade is far enough from zero
+//--- (|t| >= 1.96, about 95% confidence)
+
+const SignificanceTStat = 1.96
+
+//-----------------------------------------------------------------------------
+
 type RollingInfo struct {
-	Trades       Value `json:"trades"`
-	GrossReturns Value `json:"grossReturns"`
-	NetReturns   Value `json:"netReturns"`
+	Trades       Value   `json:"trades"`
+	GrossReturns Value   `json:"grossReturns"`
+	NetReturns   Value   `json:"netReturns"`
+	TStat        float64 `json:"tStat"`
+	Significant  bool    `json:"significant"`
+	netSquares   float64
+}
+
+//-----------------------------------------------------------------------------
+
+func (ri *RollingInfo) consolidate() {
+	count := int(ri.Trades.Total)
+	if count < 2 {
+		return
+	}
+
+	mean     := ri.NetReturns.Total / ri.Trades.Total
+	variance := (ri.netSquares - ri.Trades.Total * mean * mean) / (ri.Trades.Total - 1)
+	if variance < 0 {
+		variance = 0
+	}
+
+	tStat := stats.TStatistic(mean, math.Sqrt(variance), count)
+
+	ri.TStat       = core.Trunc2d(tStat)
+	ri.Significant = math.Abs(tStat) >= SignificanceTStat
 }
 
 //=============================================================================
@@ -193,10 +225,202 @@ type YoYRolling struct {
 //=============================================================================
 
 type Rolling struct {
-	Daily    [ 7]RollingInfo `json:"daily"`
-	Monthly  [12]RollingInfo `json:"monthly"`
-	DayYoY   []*YoYRolling   `json:"dayYoY"`
-	MonthYoY []*YoYRolling   `json:"monthYoY"`
+	Daily       [ 7]RollingInfo     `json:"daily"`
+	Monthly     [12]RollingInfo     `json:"monthly"`
+	DayYoY      []*YoYRolling       `json:"dayYoY"`
+	MonthYoY    []*YoYRolling       `json:"monthYoY"`
+	EntryHour   [24]RollingInfo     `json:"entryHour"`
+	ExitHour    [24]RollingInfo     `json:"exitHour"`
+	DayOfMonth  [31]RollingInfo     `json:"dayOfMonth"`
+	WeekOfYear  [53]RollingInfo     `json:"weekOfYear"`
+	WeekdayHour [ 7][24]RollingInfo `json:"weekdayHour"`
+}
+
+//=============================================================================
+
+type LabelAggregate struct {
+	EntryLabel   string `json:"entryLabel"`
+	ExitLabel    string `json:"exitLabel"`
+	Trades       Value  `json:"trades"`
+	GrossProfit  Value  `json:"grossProfit"`
+	NetProfit    Value  `json:"netProfit"`
+	AverageTrade Value  `json:"averageTrade"`
+	WinPerc      Value  `json:"winPerc"`
+	ProfitFactor Value  `json:"profitFactor"`
+	grossWin     Value
+	grossLoss    Value
+}
+
+//-----------------------------------------------------------------------------
+
+func NewLabelAggregate(entryLabel, exitLabel string) *LabelAggregate {
+	return &LabelAggregate{
+		EntryLabel: entryLabel,
+		ExitLabel : exitLabel,
+	}
+}
+
+//-----------------------------------------------------------------------------
+
+func (la *LabelAggregate) addTrade(tr *db.Trade, cost float64) {
+	netProfit := tr.GrossProfit - 2 * cost
+
+	updateLabelValues(&la.Trades.Total, &la.GrossProfit.Total, &la.NetProfit.Total, &la.WinPerc.Total, &la.grossWin.Total, &la.grossLoss.Total, tr.GrossProfit, netProfit)
+
+	if tr.TradeType == db.TradeTypeLong {
+		updateLabelValues(&la.Trades.Long, &la.GrossProfit.Long, &la.NetProfit.Long, &la.WinPerc.Long, &la.grossWin.Long, &la.grossLoss.Long, tr.GrossProfit, netProfit)
+	} else {
+		updateLabelValues(&la.Trades.Short, &la.GrossProfit.Short, &la.NetProfit.Short, &la.WinPerc.Short, &la.grossWin.Short, &la.grossLoss.Short, tr.GrossProfit, netProfit)
+	}
+}
+
+//-----------------------------------------------------------------------------
+
+func (la *LabelAggregate) consolidate() {
+	la.AverageTrade.Total, la.WinPerc.Total, la.ProfitFactor.Total = consolidateLabelValues(la.Trades.Total, la.NetProfit.Total, la.WinPerc.Total, la.grossWin.Total, la.grossLoss.Total)
+	la.AverageTrade.Long,  la.WinPerc.Long,  la.ProfitFactor.Long  = consolidateLabelValues(la.Trades.Long,  la.NetProfit.Long,  la.WinPerc.Long,  la.grossWin.Long,  la.grossLoss.Long)
+	la.AverageTrade.Short, la.WinPerc.Short, la.ProfitFactor.Short = consolidateLabelValues(la.Trades.Short, la.NetProfit.Short, la.WinPerc.Short, la.grossWin.Short, la.grossLoss.Short)
+
+	la.GrossProfit.Total = core.Trunc2d(la.GrossProfit.Total)
+	la.GrossProfit.Long  = core.Trunc2d(la.GrossProfit.Long)
+	la.GrossProfit.Short = core.Trunc2d(la.GrossProfit.Short)
+	la.NetProfit  .Total = core.Trunc2d(la.NetProfit.Total)
+	la.NetProfit  .Long  = core.Trunc2d(la.NetProfit.Long)
+	la.NetProfit  .Short = core.Trunc2d(la.NetProfit.Short)
+}
+
+//-----------------------------------------------------------------------------
+
+func updateLabelValues(trades, grossProfit, netProfit, wins, grossWin, grossLoss *float64, gross, net float64) {
+	*trades++
+	*grossProfit += gross
+	*netProfit   += net
+
+	if net > 0 {
+		*wins++
+		*grossWin += net
+	} else {
+		*grossLoss += net
+	}
+}
+
+//-----------------------------------------------------------------------------
+
+func consolidateLabelValues(trades, netProfit, wins, grossWin, grossLoss float64) (float64, float64, float64) {
+	if trades == 0 {
+		return 0, 0, 0
+	}
+
+	avgTrade := core.Trunc2d(netProfit / trades)
+	winPerc  := core.Trunc2d(wins / trades * 100)
+
+	return avgTrade, winPerc, calcProfitFactor(grossWin, grossLoss)
+}
+
+//=============================================================================
+
+type Labels struct {
+	Entry     []*LabelAggregate `json:"entry"`
+	Exit      []*LabelAggregate `json:"exit"`
+	EntryExit []*LabelAggregate `json:"entryExit"`
+}
+
+//=============================================================================
+
+type ConfidenceInterval struct {
+	Value float64 `json:"value"`
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
+}
+
+//=============================================================================
+//--- If all trades are equal there is no deviation and the t-test cannot be
+//--- computed: Computable is false and the trades are not significant
+
+type Significance struct {
+	Trades       int                `json:"trades"`
+	Alpha        float64            `json:"alpha"`
+	Confidence   float64            `json:"confidence"`
+	Computable   bool               `json:"computable"`
+	TStatistic   float64            `json:"tStatistic"`
+	PValue       float64            `json:"pValue"`
+	Significant  bool               `json:"significant"`
+	MinTrades    int                `json:"minTrades"`
+	Seed         int64              `json:"seed"`
+	AverageTrade ConfidenceInterval `json:"averageTrade"`
+	SharpeRatio  ConfidenceInterval `json:"sharpeRatio"`
+	ProfitFactor ConfidenceInterval `json:"profitFactor"`
+}
+
+//=============================================================================
+
+type DailySeries struct {
+	Days        []datatype.IntDate `json:"days"`
+	Returns     []float64          `json:"returns"`
+	ReturnsPerc []float64          `json:"returnsPerc"`
+	Equity      []float64          `json:"equity"`
+}
+
+//=============================================================================
+
+type Capital struct {
+	Capital          float64      `json:"capital"`
+	MarginValue      float64      `json:"marginValue"`
+	Years            float64      `json:"years"`
+	NetProfit        float64      `json:"netProfit"`
+	ReturnPerc       float64      `json:"returnPerc"`
+	Cagr             float64      `json:"cagr"`
+	AnnualVolatility float64      `json:"annualVolatility"`
+	SharpeRatio      float64      `json:"sharpeRatio"`
+	ReturnOnMargin   float64      `json:"returnOnMargin"`
+	MaxDrawdown      float64      `json:"maxDrawdown"`
+	MaxDrawdownPerc  float64      `json:"maxDrawdownPerc"`
+	Daily            *DailySeries `json:"daily"`
+}
+
+//=============================================================================
+//--- Days are the ones of the system, while AlignedDays is the number of them
+//--- having a benchmark return too
+
+type Benchmark struct {
+	DataProductId       uint               `json:"dataProductId"`
+	AlignedDays         int                `json:"alignedDays"`
+	Correlation         float64            `json:"correlation"`
+	Beta                float64            `json:"beta"`
+	Alpha               float64            `json:"alpha"`
+	UpCapture           float64            `json:"upCapture"`
+	DownCapture         float64            `json:"downCapture"`
+	ReturnPerc          float64            `json:"returnPerc"`
+	BenchmarkReturnPerc float64            `json:"benchmarkReturnPerc"`
+	Days                []datatype.IntDate `json:"days"`
+	Equity              []float64          `json:"equity"`
+	BenchmarkEquity     []float64          `json:"benchmarkEquity"`
+}
+
+//=============================================================================
+//--- DrawdownContribution is the net profit of the system between the peak
+//--- and the trough of the portfolio max (net) drawdown
+
+type SystemContribution struct {
+	Id                       uint    `json:"id"`
+	Name                     string  `json:"name"`
+	Trades                   int     `json:"trades"`
+	GrossProfit              float64 `json:"grossProfit"`
+	NetProfit                float64 `json:"netProfit"`
+	NetProfitPerc            float64 `json:"netProfitPerc"`
+	MaxDrawdown              float64 `json:"maxDrawdown"`
+	DrawdownContribution     float64 `json:"drawdownContribution"`
+	DrawdownContributionPerc float64 `json:"drawdownContributionPerc"`
+}
+
+//=============================================================================
+
+type Portfolio struct {
+	Portfolio     *db.Portfolio         `json:"portfolio"`
+	Currency      string                `json:"currency"`
+	DrawdownStart *time.Time            `json:"drawdownStart"`
+	DrawdownEnd   *time.Time            `json:"drawdownEnd"`
+	Systems       []*SystemContribution `json:"systems"`
 }
 
 //=============================================================================
@@ -213,6 +437,13 @@ type AnalysisResponse struct {
 	Aggregates      Aggregates        `json:"aggregates"`
 	Distributions   Distributions     `json:"distributions"`
 	Rolling         Rolling           `json:"rolling"`
+	Labels          Labels            `json:"labels"`
+	Significance    *Significance     `json:"significance"`
+	Capital         *Capital          `json:"capital"`
+	Benchmark       *Benchmark        `json:"benchmark"`
+	Portfolio       *Portfolio        `json:"portfolio"`
+
+	pc *core.ProfitCalculator
 }
 
 //=============================================================================
@@ -0,0 +1,155 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+
+package performance
+
+import (
+	"slices"
+
+	"github.com/tradalia/core/datatype"
+	"github.com/tradalia/portfolio-trader/pkg/core"
+	"github.com/tradalia/portfolio-trader/pkg/core/stats"
+	"github.com/tradalia/portfolio-trader/pkg/platform"
+)
+
+//=============================================================================
+//===
+//=== Benchmark comparison
+//===
+//=============================================================================
+//--- Compares the daily percentage returns of the system (calculated on the
+//--- capital) with the daily returns of the benchmark's prices. The series are
+//--- aligned on the days of the system: if the benchmark has no return on one
+//--- of them, its return is 0
+
+func calcBenchmark(res *AnalysisResponse, dataProductId uint, man *platform.DataProductAnalysisResponse) {
+	if man == nil || res.Capital == nil {
+		return
+	}
+
+	benchReturns := buildBenchmarkReturns(man.DailyResults)
+	daily        := res.Capital.Daily
+
+	b := &Benchmark{
+		DataProductId: dataProductId,
+	}
+
+	var sysList   []float64
+	var benchList []float64
+
+	sysEquity   := res.Capital.Capital
+	benchEquity := res.Capital.Capital
+
+	for i, day := range daily.Days {
+		benchRet, ok := benchReturns[day]
+		if ok {
+			b.AlignedDays++
+		}
+
+		sysRet := daily.ReturnsPerc[i]
+
+		sysList   = append(sysList,   sysRet)
+		benchList = append(benchList, benchRet)
+
+		sysEquity   = daily.Equity[i]
+		benchEquity = benchEquity * (1 + benchRet / 100)
+
+		b.Days            = append(b.Days,            day)
+		b.Equity          = append(b.Equity,          core.Trunc2d(sysEquity))
+		b.BenchmarkEquity = append(b.BenchmarkEquity, core.Trunc2d(benchEquity))
+	}
+
+	if b.AlignedDays < 2 {
+		res.Benchmark = b
+		return
+	}
+
+	sysMean   := stats.Mean(sysList)
+	benchMean := stats.Mean(benchList)
+	benchStd  := stats.StdDev(benchList, benchMean)
+
+	b.Correlation = core.Trunc2d(stats.Correlation(sysList, benchList))
+
+	if benchStd != 0 {
+		beta   := stats.Covariance(sysList, benchList) / (benchStd * benchStd)
+		b.Beta  = core.Trunc2d(beta)
+		b.Alpha = core.Trunc2d((sysMean - beta * benchMean) * stats.TradingDaysPerYear)
+	}
+
+	b.UpCapture   = core.Trunc2d(calcCapture(sysList, benchList, true))
+	b.DownCapture = core.Trunc2d(calcCapture(sysList, benchList, false))
+
+	b.ReturnPerc          = core.Trunc2d((sysEquity   / res.Capital.Capital - 1) * 100)
+	b.BenchmarkReturnPerc = core.Trunc2d((benchEquity / res.Capital.Capital - 1) * 100)
+
+	res.Benchmark = b
+}
+
+//=============================================================================
+//--- Returns the daily percentage change of the prices, keyed by day
+
+func buildBenchmarkReturns(list []*platform.DailyResult) map[datatype.IntDate]float64 {
+	res := map[datatype.IntDate]float64{}
+
+	sorted := slices.Clone(list)
+	slices.SortFunc(sorted, func(a, b *platform.DailyResult) int {
+		return int(a.Date) - int(b.Date)
+	})
+
+	var prev *platform.DailyResult
+
+	for _, dr := range sorted {
+		if prev != nil && prev.Price != 0 {
+			res[dr.Date] = (dr.Price / prev.Price - 1) * 100
+		}
+
+		prev = dr
+	}
+
+	return res
+}
+
+//=============================================================================
+//--- Ratio between the average system return and the average benchmark return,
+//--- considering only the days when the benchmark went up (or down)
+
+func calcCapture(sysList, benchList []float64, up bool) float64 {
+	sysSum   := 0.0
+	benchSum := 0.0
+
+	for i, bench := range benchList {
+		if (up && bench > 0) || (!up && bench < 0) {
+			sysSum   += sysList[i]
+			benchSum += bench
+		}
+	}
+
+	if benchSum == 0 {
+		return 0
+	}
+
+	return sysSum / benchSum * 100
+}
+
+//=============================================================================
@@ -0,0 +1,80 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+
+
+package performance
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/tradalia/core/datatype"
+	"github.com/tradalia/portfolio-trader/pkg/platform"
+)
+
+//=============================================================================
+
+func TestCalcBenchmark(t *testing.T) {
+	days := []datatype.IntDate{ 20250102, 20250103, 20250106, 20250107 }
+
+	res := &AnalysisResponse{
+		Capital: &Capital{
+			Capital: 1000,
+			Daily  : buildDailySeries(days, []float64{ 10, -20, 30, 5 }, 1000),
+		},
+	}
+
+	//--- There is no price on 2025-01-06: the benchmark return is 0 on that day
+
+	man := &platform.DataProductAnalysisResponse{
+		DailyResults: []*platform.DailyResult{
+			{ Date: 20250101, Price: 100 },
+			{ Date: 20250102, Price: 110 },
+			{ Date: 20250103, Price:  99 },
+			{ Date: 20250107, Price: 108.9 },
+		},
+	}
+
+	calcBenchmark(res, 1, man)
+
+	b := res.Benchmark
+	if b == nil || b.AlignedDays != 3 || !reflect.DeepEqual(b.Days, days) {
+		t.Fatalf("Bad benchmark: Expected 3 aligned days over %v and got %+v", days, b)
+	}
+
+	if expected := []float64{ 1010, 990, 1020, 1025 }; !reflect.DeepEqual(b.Equity, expected) {
+		t.Errorf("Bad equity: Expected %v and got %v", expected, b.Equity)
+	}
+
+	if expected := []float64{ 1100, 990, 990, 1089 }; !reflect.DeepEqual(b.BenchmarkEquity, expected) {
+		t.Errorf("Bad benchmark equity: Expected %v and got %v", expected, b.BenchmarkEquity)
+	}
+
+	if math.Abs(b.ReturnPerc - 2.5) > 0.01 || math.Abs(b.BenchmarkReturnPerc - 8.9) > 0.01 {
+		t.Errorf("Bad returns: Expected 2.5 and 8.9 and got %v and %v", b.ReturnPerc, b.BenchmarkReturnPerc)
+	}
+}
+
+//=============================================================================
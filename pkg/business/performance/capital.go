@@ -0,0 +1,122 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+
+package performance
+
+import (
+	"time"
+
+	"github.com/tradalia/core/datatype"
+	"github.com/tradalia/portfolio-trader/pkg/core"
+	"github.com/tradalia/portfolio-trader/pkg/core/stats"
+	"github.com/tradalia/portfolio-trader/pkg/db"
+)
+
+//=============================================================================
+//===
+//=== Capital based metrics
+//===
+//=============================================================================
+//--- When the capital is not provided, the margin of the trading systems is used
+
+func calcCapital(res *AnalysisResponse, returns *[]db.DailyReturn, capital float64) {
+	margin := res.pc.MarginValue()
+
+	if capital <= 0 {
+		capital = margin
+	}
+
+	if capital <= 0 {
+		return
+	}
+
+	days, netReturns := core.BuildNetDailyReturns(returns, res.pc)
+	if len(days) == 0 {
+		return
+	}
+
+	daily := buildDailySeries(days, netReturns, capital)
+
+	netProfit   := daily.Equity[len(daily.Equity) -1] - capital
+	_, maxDD    := core.BuildDrawDown(core.BuildEquity(&netReturns))
+	years       := calcYears(days[0], days[len(days) -1])
+	mean        := stats.Mean(daily.ReturnsPerc)
+	stdDev      := stats.StdDev(daily.ReturnsPerc, mean)
+
+	c := &Capital{
+		Capital         : capital,
+		MarginValue     : margin,
+		Years           : core.Trunc2d(years),
+		NetProfit       : core.Trunc2d(netProfit),
+		ReturnPerc      : core.Trunc2d(netProfit / capital * 100),
+		Cagr            : core.Trunc2d(stats.Cagr(capital, capital + netProfit, years)),
+		AnnualVolatility: core.Trunc2d(stats.AnnualizedStdDev(stdDev)),
+		SharpeRatio     : core.Trunc2d(stats.AnnualizedSharpeRatio(mean, stdDev)),
+		MaxDrawdown     : core.Trunc2d(maxDD),
+		MaxDrawdownPerc : core.Trunc2d(maxDD / capital * 100),
+		Daily           : daily,
+	}
+
+	if margin > 0 {
+		c.ReturnOnMargin = core.Trunc2d(netProfit / margin * 100)
+	}
+
+	res.Capital = c
+}
+
+//=============================================================================
+//--- Percentage returns are calculated on the equity at the end of the previous day
+
+func buildDailySeries(days []datatype.IntDate, returns []float64, capital float64) *DailySeries {
+	ds := &DailySeries{
+		Days       : days,
+		Returns    : returns,
+		ReturnsPerc: make([]float64, len(returns)),
+		Equity     : make([]float64, len(returns)),
+	}
+
+	equity := capital
+
+	for i, value := range returns {
+		if equity > 0 {
+			ds.ReturnsPerc[i] = value / equity * 100
+		}
+
+		equity += value
+		ds.Equity[i] = equity
+	}
+
+	return ds
+}
+
+//=============================================================================
+
+func calcYears(from, to datatype.IntDate) float64 {
+	fromTime := from.ToDateTime(false, time.UTC)
+	toTime   := to  .ToDateTime(true,  time.UTC)
+
+	return toTime.Sub(fromTime).Hours() / 24 / 365.25
+}
+
+//=============================================================================
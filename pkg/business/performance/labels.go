@@ -0,0 +1,117 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+
+package performance
+
+import (
+	"math"
+	"sort"
+
+	"github.com/tradalia/portfolio-trader/pkg/core"
+)
+
+//=============================================================================
+
+type labelKey struct {
+	entry string
+	exit  string
+}
+
+//=============================================================================
+//===
+//=== Performance by entry/exit signal labels
+//===
+//=============================================================================
+
+func calcLabels(res *AnalysisResponse) {
+	entryMap := map[labelKey]*LabelAggregate{}
+	exitMap  := map[labelKey]*LabelAggregate{}
+	pairMap  := map[labelKey]*LabelAggregate{}
+
+	for _, tr := range *res.Trades {
+		cost := res.pc.TradeCost(&tr)
+
+		getLabelAggregate(entryMap, tr.EntryLabel, "")          .addTrade(&tr, cost)
+		getLabelAggregate(exitMap,  "",            tr.ExitLabel).addTrade(&tr, cost)
+		getLabelAggregate(pairMap,  tr.EntryLabel, tr.ExitLabel).addTrade(&tr, cost)
+	}
+
+	res.Labels.Entry     = toSortedLabelList(entryMap)
+	res.Labels.Exit      = toSortedLabelList(exitMap)
+	res.Labels.EntryExit = toSortedLabelList(pairMap)
+}
+
+//=============================================================================
+
+func getLabelAggregate(m map[labelKey]*LabelAggregate, entryLabel, exitLabel string) *LabelAggregate {
+	key := labelKey{ entry: entryLabel, exit: exitLabel }
+
+	la, ok := m[key]
+	if !ok {
+		la = NewLabelAggregate(entryLabel, exitLabel)
+		m[key] = la
+	}
+
+	return la
+}
+
+//=============================================================================
+//--- Groups are returned from the most to the least profitable one
+
+func toSortedLabelList(m map[labelKey]*LabelAggregate) []*LabelAggregate {
+	list := []*LabelAggregate{}
+
+	for _, la := range m {
+		la.consolidate()
+		list = append(list, la)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].NetProfit.Total != list[j].NetProfit.Total {
+			return list[i].NetProfit.Total > list[j].NetProfit.Total
+		}
+
+		if list[i].EntryLabel != list[j].EntryLabel {
+			return list[i].EntryLabel < list[j].EntryLabel
+		}
+
+		return list[i].ExitLabel < list[j].ExitLabel
+	})
+
+	return list
+}
+
+//=============================================================================
+//--- grossLoss is negative. Without losing trades the factor cannot be
+//--- calculated (it would be +Inf) so 0 is returned
+
+func calcProfitFactor(grossWin, grossLoss float64) float64 {
+	if grossLoss == 0 {
+		return 0
+	}
+
+	return core.Trunc2d(grossWin / math.Abs(grossLoss))
+}
+
+//=============================================================================
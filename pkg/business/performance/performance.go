@@ -31,18 +31,73 @@ import (
 	"github.com/tradalia/portfolio-trader/pkg/core"
 	"github.com/tradalia/portfolio-trader/pkg/core/stats"
 	"github.com/tradalia/portfolio-trader/pkg/db"
+	"github.com/tradalia/portfolio-trader/pkg/platform"
 )
 
 //=============================================================================
 
-func GetPerformanceAnalysis(ts *db.TradingSystem, trades *[]db.Trade, returns *[]db.DailyReturn) *AnalysisResponse {
+func GetPerformanceAnalysis(ts *db.TradingSystem, trades *[]db.Trade, returns *[]db.DailyReturn, req *AnalysisRequest, loc *time.Location, bench *platform.DataProductAnalysisResponse) *AnalysisResponse {
+	tsMap := map[uint]*db.TradingSystem{ ts.Id: ts }
+
 	res := AnalysisResponse{}
 	res.TradingSystem = ts
-	res.Trades        = trades
+	res.pc            = core.NewProfitCalculator(tsMap)
+
+	runAnalysis(&res, trades, returns, req, loc)
+
+	if req.Benchmark != nil {
+		calcBenchmark(&res, BenchmarkDataProductId(ts, req.Benchmark), bench)
+	}
+
+	return &res
+}
+
+//=============================================================================
+//--- Trades and returns of all trading systems are merged. Costs and margins
+//--- are the ones of each system, converted by the profit calculator
+
+func GetPortfolioPerformanceAnalysis(p *db.Portfolio, tsMap map[uint]*db.TradingSystem, pc *core.ProfitCalculator, trades *[]db.Trade, returns *[]db.DailyReturn, req *AnalysisRequest, loc *time.Location, bench *platform.DataProductAnalysisResponse) *AnalysisResponse {
+	res := AnalysisResponse{}
+	res.pc = pc
+
+	trades  = pc.ConvertTrades(trades)
+	returns = pc.ConvertDailyReturns(returns)
+
+	runAnalysis(&res, trades, returns, req, loc)
+	calcPortfolio(&res, p, tsMap)
+
+	if req.Benchmark != nil {
+		calcBenchmark(&res, req.Benchmark.DataProductId, bench)
+	}
 
-	allEq  , allMaxGrossDD  , allMaxNetDD   := calcEquities(ts, trades, db.TradeTypeAll)
-	longEq , longMaxGrossDD , longMaxNetDD  := calcEquities(ts, trades, db.TradeTypeLong)
-	shortEq, shortMaxGrossDD, shortMaxNetDD := calcEquities(ts, trades, db.TradeTypeShort)
+	return &res
+}
+
+//=============================================================================
+
+//=============================================================================
+
+func BenchmarkDataProductId(ts *db.TradingSystem, req *BenchmarkRequest) uint {
+	if req.DataProductId != 0 {
+		return req.DataProductId
+	}
+
+	return ts.DataProductId
+}
+
+//=============================================================================
+//===
+//=== Private functions
+//===
+//=============================================================================
+
+func runAnalysis(res *AnalysisResponse, trades *[]db.Trade, returns *[]db.DailyReturn, req *AnalysisRequest, loc *time.Location) {
+	res.Trades           = trades
+	res.General.Timezone = loc.String()
+
+	allEq  , allMaxGrossDD  , allMaxNetDD   := calcEquities(res.pc, trades, db.TradeTypeAll)
+	longEq , longMaxGrossDD , longMaxNetDD  := calcEquities(res.pc, trades, db.TradeTypeLong)
+	shortEq, shortMaxGrossDD, shortMaxNetDD := calcEquities(res.pc, trades, db.TradeTypeShort)
 
 	res.AllEquities   = allEq
 	res.LongEquities  = longEq
@@ -69,23 +124,20 @@ func GetPerformanceAnalysis(ts *db.TradingSystem, trades *[]db.Trade, returns *[
 	res.Net  .AverageTrade.Long  = calcAvgTrade(res.Net  .Profit.Long , longEq.Trades)
 	res.Net  .AverageTrade.Short = calcAvgTrade(res.Net  .Profit.Short, shortEq.Trades)
 
-	calcAggregates   (&res)
-	updateGeneralInfo(&res)
-	calcDistributions(&res, returns)
-	calcRolling      (&res)
-
-	return &res
+	calcAggregates   (res)
+	updateGeneralInfo(res)
+	calcDistributions(res, returns)
+	calcRolling      (res, loc)
+	calcLabels       (res)
+	calcSignificance (res, req.Seed)
+	calcCapital      (res, returns, req.Capital)
 }
 
-//=============================================================================
-//===
-//=== Private functions
-//===
 //=============================================================================
 
-func calcEquities(ts *db.TradingSystem, trades *[]db.Trade, tradeType string) (*Equities, float64, float64) {
+func calcEquities(pc *core.ProfitCalculator, trades *[]db.Trade, tradeType string) (*Equities, float64, float64) {
 	timeSlice, grossProfits := core.BuildGrossProfits(trades, tradeType)
-	netProfits              := core.BuildNetProfits(grossProfits, ts.CostPerOperation)
+	netProfits              := pc.NetProfits(trades, tradeType)
 
 	grossEquity := core.BuildEquity(grossProfits)
 	netEquity   := core.BuildEquity(netProfits)
@@ -134,12 +186,14 @@ func calcAggregates(res *AnalysisResponse) {
 //=============================================================================
 
 func calcYearAggregates(res *AnalysisResponse) {
-	cost := float64(res.TradingSystem.CostPerOperation)
+	pc   := res.pc
 	list := []*AnnualAggregate{}
 
 	var currYear *AnnualAggregate
 
 	for _, tr := range *res.Trades {
+		cost := pc.TradeCost(&tr)
+
 		if currYear == nil {
 			//--- Beginning of a new year
 
@@ -202,15 +256,23 @@ func calcDistributions(res *AnalysisResponse, returns *[]db.DailyReturn) {
 	list := core.ToNonZeroDailyReturnSlice(returns)
 	dist.Daily = calcDistribution(list)
 
-	if dist.Daily != nil {
-		dist.AnnualSharpeRatio = core.Trunc2d(dist.Daily.SharpeRatio * 16)
-		dist.AnnualStandardDev = core.Trunc2d(dist.Daily.StandardDev * 16)
+	//--- Annual values must include the days without trading, otherwise
+	//--- systems that trade rarely get an inflated sharpe ratio
+
+	_, allDays := core.BuildDailyReturns(returns, 0)
+
+	if len(allDays) > 1 {
+		mean   := stats.Mean(allDays)
+		stdDev := stats.StdDev(allDays, mean)
+
+		dist.AnnualSharpeRatio = core.Trunc2d(stats.AnnualizedSharpeRatio(mean, stdDev))
+		dist.AnnualStandardDev = core.Trunc2d(stats.AnnualizedStdDev(stdDev))
 	}
 
 	//--- All (gross + net)
 
 	_, allGross := core.BuildGrossProfits(res.Trades, db.TradeTypeAll)
-	allNet      := core.BuildNetProfits(allGross, res.TradingSystem.CostPerOperation)
+	allNet      := res.pc.NetProfits(res.Trades, db.TradeTypeAll)
 
 	dist.TradesAllGross = calcDistribution(*allGross)
 	dist.TradesAllNet   = calcDistribution(*allNet)
@@ -218,7 +280,7 @@ func calcDistributions(res *AnalysisResponse, returns *[]db.DailyReturn) {
 	//--- Long (gross + net)
 
 	_, longGross := core.BuildGrossProfits(res.Trades, db.TradeTypeLong)
-	longNet      := core.BuildNetProfits(longGross, res.TradingSystem.CostPerOperation)
+	longNet      := res.pc.NetProfits(res.Trades, db.TradeTypeLong)
 
 	dist.TradesLongGross = calcDistribution(*longGross)
 	dist.TradesLongNet   = calcDistribution(*longNet)
@@ -226,7 +288,7 @@ func calcDistributions(res *AnalysisResponse, returns *[]db.DailyReturn) {
 	//--- Short (gross + net)
 
 	_, shortGross := core.BuildGrossProfits(res.Trades, db.TradeTypeShort)
-	shortNet      := core.BuildNetProfits(shortGross, res.TradingSystem.CostPerOperation)
+	shortNet      := res.pc.NetProfits(res.Trades, db.TradeTypeShort)
 
 	dist.TradesShortGross = calcDistribution(*shortGross)
 	dist.TradesShortNet   = calcDistribution(*shortNet)
@@ -268,40 +330,90 @@ func calcDistribution(data []float64) *Distribution {
 
 //=============================================================================
 
-func calcRolling(res *AnalysisResponse) {
-	costPerOper := res.TradingSystem.CostPerOperation
+func calcRolling(res *AnalysisResponse, loc *time.Location) {
+	rolling := &res.Rolling
 
 	for _, tr := range *res.Trades {
-		year := tr.EntryDate.Year()
-		dow  := int(tr.EntryDate.Weekday())
-		mon  := int(tr.EntryDate.Month()) -1
+		costPerOper := res.pc.TradeCost(&tr)
+		entry := tr.EntryDate.In(loc)
+		exit  := tr.ExitDate .In(loc)
+
+		year    := entry.Year()
+		dow     := int(entry.Weekday())
+		mon     := int(entry.Month()) -1
+		dom     := entry.Day() -1
+		hour    := entry.Hour()
+		exHour  := exit.Hour()
+		_, week := entry.ISOWeek()
+
+		updateRollingInfo(&tr, &rolling.Daily      [dow],       costPerOper)
+		updateRollingInfo(&tr, &rolling.Monthly    [mon],       costPerOper)
+		updateRollingInfo(&tr, &rolling.EntryHour  [hour],      costPerOper)
+		updateRollingInfo(&tr, &rolling.ExitHour   [exHour],    costPerOper)
+		updateRollingInfo(&tr, &rolling.DayOfMonth [dom],       costPerOper)
+		updateRollingInfo(&tr, &rolling.WeekOfYear [week -1],   costPerOper)
+		updateRollingInfo(&tr, &rolling.WeekdayHour[dow][hour], costPerOper)
+
+		rolling.DayYoY   = updateYoY(rolling.DayYoY,   year, &tr, dow, costPerOper,  7)
+		rolling.MonthYoY = updateYoY(rolling.MonthYoY, year, &tr, mon, costPerOper, 12)
+	}
 
-		dowRI := &res.Rolling.Daily  [dow]
-		monRI := &res.Rolling.Monthly[mon]
+	consolidateRolling(rolling)
+}
 
-		updateRollingInfo(&tr, dowRI, costPerOper)
-		updateRollingInfo(&tr, monRI, costPerOper)
+//=============================================================================
+
+func consolidateRolling(rolling *Rolling) {
+	consolidateRollingInfo(rolling.Daily[:])
+	consolidateRollingInfo(rolling.Monthly[:])
+	consolidateRollingInfo(rolling.EntryHour[:])
+	consolidateRollingInfo(rolling.ExitHour[:])
+	consolidateRollingInfo(rolling.DayOfMonth[:])
+	consolidateRollingInfo(rolling.WeekOfYear[:])
+
+	for i := range rolling.WeekdayHour {
+		consolidateRollingInfo(rolling.WeekdayHour[i][:])
+	}
+
+	for _, yoy := range rolling.DayYoY {
+		for _, ri := range yoy.Data {
+			ri.consolidate()
+		}
+	}
 
-		res.Rolling.DayYoY   = updateYoY(res.Rolling.DayYoY,   year, &tr, dow, costPerOper,  7)
-		res.Rolling.MonthYoY = updateYoY(res.Rolling.MonthYoY, year, &tr, mon, costPerOper, 12)
+	for _, yoy := range rolling.MonthYoY {
+		for _, ri := range yoy.Data {
+			ri.consolidate()
+		}
+	}
+}
+
+//=============================================================================
+
+func consolidateRollingInfo(list []RollingInfo) {
+	for i := range list {
+		list[i].consolidate()
 	}
 }
 
 //=============================================================================
 
 func updateRollingInfo(tr *db.Trade, ri *RollingInfo, costPerOper float64) {
+	netProfit := tr.GrossProfit - 2 * costPerOper
+
 	ri.Trades.Total++
 	ri.GrossReturns.Total += tr.GrossProfit
-	ri.NetReturns.Total   += tr.GrossProfit - 2 * costPerOper
+	ri.NetReturns.Total   += netProfit
+	ri.netSquares         += netProfit * netProfit
 
 	if tr.TradeType == db.TradeTypeLong {
 		ri.Trades      .Long++
 		ri.GrossReturns.Long += tr.GrossProfit
-		ri.NetReturns  .Long += tr.GrossProfit - 2 * costPerOper
+		ri.NetReturns  .Long += netProfit
 	} else {
 		ri.Trades      .Short++
 		ri.GrossReturns.Short += tr.GrossProfit
-		ri.NetReturns  .Short += tr.GrossProfit - 2 * costPerOper
+		ri.NetReturns  .Short += netProfit
 	}
 }
 
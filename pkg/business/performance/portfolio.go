@@ -0,0 +1,158 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+
+package performance
+
+import (
+	"sort"
+
+	"github.com/tradalia/portfolio-trader/pkg/core"
+	"github.com/tradalia/portfolio-trader/pkg/db"
+)
+
+//=============================================================================
+//===
+//=== Contribution of each trading system to the portfolio
+//===
+//=============================================================================
+
+func calcPortfolio(res *AnalysisResponse, p *db.Portfolio, tsMap map[uint]*db.TradingSystem) {
+	sysMap := map[uint]*SystemContribution{}
+
+	for id, ts := range tsMap {
+		sysMap[id] = &SystemContribution{
+			Id  : id,
+			Name: ts.Name,
+		}
+	}
+
+	//--- Profits and standalone drawdowns
+
+	equMap := map[uint]*[]float64{}
+
+	for i := range *res.Trades {
+		tr  := &(*res.Trades)[i]
+		sc, ok := sysMap[tr.TradingSystemId]
+		if !ok {
+			continue
+		}
+
+		sc.Trades++
+		sc.GrossProfit += tr.GrossProfit
+		sc.NetProfit   += res.pc.NetProfit(tr)
+
+		equity, ok := equMap[tr.TradingSystemId]
+		if !ok {
+			equity = &[]float64{}
+			equMap[tr.TradingSystemId] = equity
+		}
+
+		*equity = append(*equity, sc.NetProfit)
+	}
+
+	for id, equity := range equMap {
+		_, sysMap[id].MaxDrawdown = core.BuildDrawDown(equity)
+	}
+
+	//--- Drawdown contributions
+
+	peak, trough := findMaxDrawdownRange(res.AllEquities.NetEquity)
+	port := &Portfolio{
+		Portfolio: p,
+		Currency : res.pc.Currency(),
+		Systems  : []*SystemContribution{},
+	}
+
+	if trough >= 0 {
+		for i := peak +1; i <= trough; i++ {
+			tr := &(*res.Trades)[i]
+			if sc, ok := sysMap[tr.TradingSystemId]; ok {
+				sc.DrawdownContribution += res.pc.NetProfit(tr)
+			}
+		}
+
+		if peak >= 0 {
+			port.DrawdownStart = (*res.Trades)[peak].ExitDate
+		} else {
+			port.DrawdownStart = (*res.Trades)[0].ExitDate
+		}
+
+		port.DrawdownEnd = (*res.Trades)[trough].ExitDate
+	}
+
+	//--- Percentages and rounding
+
+	netProfit   := res.Net.Profit.Total
+	maxDrawdown := res.Net.MaxDrawdown.Total
+
+	for _, sc := range sysMap {
+		if netProfit != 0 {
+			sc.NetProfitPerc = core.Trunc2d(sc.NetProfit / netProfit * 100)
+		}
+
+		if maxDrawdown != 0 {
+			sc.DrawdownContributionPerc = core.Trunc2d(sc.DrawdownContribution / maxDrawdown * 100)
+		}
+
+		sc.GrossProfit          = core.Trunc2d(sc.GrossProfit)
+		sc.NetProfit            = core.Trunc2d(sc.NetProfit)
+		sc.MaxDrawdown          = core.Trunc2d(sc.MaxDrawdown)
+		sc.DrawdownContribution = core.Trunc2d(sc.DrawdownContribution)
+
+		port.Systems = append(port.Systems, sc)
+	}
+
+	sort.Slice(port.Systems, func(i, j int) bool {
+		return port.Systems[i].Id < port.Systems[j].Id
+	})
+
+	res.Portfolio = port
+}
+
+//=============================================================================
+//--- Returns the index of the peak (-1 if the peak is the initial zero) and
+//--- of the trough of the max drawdown. The trough is -1 if there is no drawdown
+
+func findMaxDrawdownRange(equity *[]float64) (int, int) {
+	maxProfit := 0.0
+	maxIndex  := -1
+	maxDD     := 0.0
+	peak      := -1
+	trough    := -1
+
+	for i, value := range *equity {
+		if value >= maxProfit {
+			maxProfit = value
+			maxIndex  = i
+		} else if value - maxProfit < maxDD {
+			maxDD  = value - maxProfit
+			peak   = maxIndex
+			trough = i
+		}
+	}
+
+	return peak, trough
+}
+
+//=============================================================================
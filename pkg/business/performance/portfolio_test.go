@@ -0,0 +1,109 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+
+
+package performance
+
+import (
+	"testing"
+
+	"github.com/tradalia/portfolio-trader/pkg/core"
+	"github.com/tradalia/portfolio-trader/pkg/db"
+	"github.com/tradalia/portfolio-trader/pkg/db/dbtest"
+)
+
+//=============================================================================
+
+func TestFindMaxDrawdownRange(t *testing.T) {
+	cases := []struct {
+		equity []float64
+		peak   int
+		trough int
+	}{
+		{ []float64{},                  -1, -1 },
+		{ []float64{ 1, 2, 3 },         -1, -1 },
+		{ []float64{ -5, -2 },          -1,  0 },
+		{ []float64{ 10, 20, 5, 0, 30 }, 1,  3 },
+		{ []float64{ 5, 0, 10, 2, 12 },  2,  3 },
+	}
+
+	for _, c := range cases {
+		peak, trough := findMaxDrawdownRange(&c.equity)
+
+		if peak != c.peak || trough != c.trough {
+			t.Errorf("Bad drawdown range for %v: Expected [%v,%v] and got [%v,%v]", c.equity, c.peak, c.trough, peak, trough)
+		}
+	}
+}
+
+//=============================================================================
+
+func TestCalcPortfolio(t *testing.T) {
+	tsMap := map[uint]*db.TradingSystem{
+		1: { Id: 1, Name: "ts1" },
+		2: { Id: 2, Name: "ts2", CostPerOperation: 1 },
+	}
+
+	//--- Net equity: 10, 20, 5, 0, 30. The max drawdown goes from trade 1 to trade 3
+
+	trades := []db.Trade{
+		dbtest.NewTrade(1, db.TradeTypeLong, dbtest.Time(1, 18), dbtest.Time(1, 18),  10, 1),
+		dbtest.NewTrade(2, db.TradeTypeLong, dbtest.Time(2, 18), dbtest.Time(2, 18),  12, 1),
+		dbtest.NewTrade(1, db.TradeTypeLong, dbtest.Time(3, 18), dbtest.Time(3, 18), -15, 1),
+		dbtest.NewTrade(2, db.TradeTypeLong, dbtest.Time(4, 18), dbtest.Time(4, 18),  -3, 1),
+		dbtest.NewTrade(1, db.TradeTypeLong, dbtest.Time(5, 18), dbtest.Time(5, 18),  30, 1),
+	}
+
+	res := &AnalysisResponse{
+		Trades     : &trades,
+		AllEquities: &Equities{ NetEquity: &[]float64{ 10, 20, 5, 0, 30 } },
+		pc         : core.NewProfitCalculator(tsMap),
+	}
+	res.Net.Profit.Total      = 30
+	res.Net.MaxDrawdown.Total = -20
+
+	calcPortfolio(res, &db.Portfolio{ Id: 1 }, tsMap)
+
+	port := res.Portfolio
+	if len(port.Systems) != 2 {
+		t.Fatalf("Bad number of systems: Expected 2 and got %v", len(port.Systems))
+	}
+
+	expected := []SystemContribution{
+		{ Id: 1, Name: "ts1", Trades: 3, GrossProfit: 25, NetProfit: 25, NetProfitPerc: 83.33, MaxDrawdown: -15, DrawdownContribution: -15, DrawdownContributionPerc: 75 },
+		{ Id: 2, Name: "ts2", Trades: 2, GrossProfit:  9, NetProfit:  5, NetProfitPerc: 16.66, MaxDrawdown:  -5, DrawdownContribution:  -5, DrawdownContributionPerc: 25 },
+	}
+
+	for i, sc := range port.Systems {
+		if *sc != expected[i] {
+			t.Errorf("Bad contribution: Expected %+v and got %+v", expected[i], *sc)
+		}
+	}
+
+	if !port.DrawdownStart.Equal(*trades[1].ExitDate) || !port.DrawdownEnd.Equal(*trades[3].ExitDate) {
+		t.Errorf("Bad drawdown window: Expected [%v,%v] and got [%v,%v]", trades[1].ExitDate, trades[3].ExitDate, port.DrawdownStart, port.DrawdownEnd)
+	}
+}
+
+//=============================================================================
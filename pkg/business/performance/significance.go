@@ -0,0 +1,153 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+
+package performance
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+	"math/rand"
+
+	"github.com/tradalia/portfolio-trader/pkg/core"
+	"github.com/tradalia/portfolio-trader/pkg/core/stats"
+	"github.com/tradalia/portfolio-trader/pkg/db"
+)
+
+//=============================================================================
+
+const (
+	SignificanceAlpha        = 0.05
+	SignificanceConfidence   = 95.0
+	BootstrapRuns            = 2000
+	MaxTradesForSignificance = 1000000
+)
+
+//=============================================================================
+//===
+//=== Statistical significance of the system's edge (net trades)
+//===
+//=============================================================================
+
+func calcSignificance(res *AnalysisResponse, seed int64) {
+	allNet := *res.pc.NetProfits(res.Trades, db.TradeTypeAll)
+
+	if len(allNet) < 2 {
+		return
+	}
+
+	if seed == 0 {
+		seed = deriveSeed(allNet)
+	}
+
+	mean   := stats.Mean(allNet)
+	stdDev := stats.SampleStdDev(allNet, mean)
+
+	sig := &Significance{
+		Trades    : len(allNet),
+		Alpha     : SignificanceAlpha,
+		Confidence: SignificanceConfidence,
+		PValue    : 1,
+		MinTrades : -1,
+		Seed      : seed,
+	}
+
+	if stdDev > 0 {
+		tStat, pValue := stats.TTest(allNet)
+
+		sig.Computable  = true
+		sig.TStatistic  = core.TruncFinite(tStat)
+		sig.PValue      = pValue
+		sig.Significant = pValue <= SignificanceAlpha
+		sig.MinTrades   = stats.MinSamplesForSignificance(mean, stdDev, SignificanceAlpha, MaxTradesForSignificance)
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+
+	sig.AverageTrade = calcConfidenceInterval(allNet, stats.Mean, rng)
+	sig.SharpeRatio  = calcConfidenceInterval(allNet, tradeSharpeRatio, rng)
+	sig.ProfitFactor = calcConfidenceInterval(allNet, tradeProfitFactor, rng)
+
+	res.Significance = sig
+}
+
+//=============================================================================
+
+func calcConfidenceInterval(data []float64, statistic func([]float64) float64, rng *rand.Rand) ConfidenceInterval {
+	interval := stats.Bootstrap(data, BootstrapRuns, SignificanceConfidence, statistic, rng)
+
+	return ConfidenceInterval{
+		Value: core.Trunc2d(statistic(data)),
+		Lower: core.Trunc2d(interval.Lower),
+		Upper: core.Trunc2d(interval.Upper),
+	}
+}
+
+//=============================================================================
+//--- A sample with all equal trades has no deviation: we return 0 instead of +Inf
+
+func tradeSharpeRatio(data []float64) float64 {
+	mean   := stats.Mean(data)
+	stdDev := stats.StdDev(data, mean)
+
+	if stdDev == 0 {
+		return 0
+	}
+
+	return mean / stdDev
+}
+
+//=============================================================================
+
+func tradeProfitFactor(data []float64) float64 {
+	grossWin  := 0.0
+	grossLoss := 0.0
+
+	for _, v := range data {
+		if v > 0 {
+			grossWin += v
+		} else {
+			grossLoss += v
+		}
+	}
+
+	return calcProfitFactor(grossWin, grossLoss)
+}
+
+//=============================================================================
+//--- Hash of the trades, used as a seed when the request doesn't provide one
+
+func deriveSeed(data []float64) int64 {
+	h   := fnv.New64a()
+	buf := make([]byte, 8)
+
+	for _, v := range data {
+		binary.LittleEndian.PutUint64(buf, math.Float64bits(v))
+		h.Write(buf)
+	}
+
+	return int64(h.Sum64() &^ (1 << 63))
+}
+
+//=============================================================================
@@ -0,0 +1,89 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+
+
+package performance
+
+import (
+	"testing"
+
+	"github.com/tradalia/portfolio-trader/pkg/core"
+	"github.com/tradalia/portfolio-trader/pkg/db"
+	"github.com/tradalia/portfolio-trader/pkg/db/dbtest"
+)
+
+//=============================================================================
+
+func TestCalcSignificance(t *testing.T) {
+	res1 := newSignificanceResponse(10, -4, 7, 3, -2, 8)
+	res2 := newSignificanceResponse(10, -4, 7, 3, -2, 8)
+
+	calcSignificance(res1, 0)
+	calcSignificance(res2, 0)
+
+	if *res1.Significance != *res2.Significance {
+		t.Errorf("Bad significance: Expected the same result and got %+v and %+v", *res1.Significance, *res2.Significance)
+	}
+
+	if !res1.Significance.Computable || res1.Significance.Seed == 0 {
+		t.Errorf("Bad significance: Expected computable with a derived seed and got %+v", *res1.Significance)
+	}
+
+	calcSignificance(res2, 42)
+
+	if res2.Significance.Seed != 42 {
+		t.Errorf("Bad seed: Expected 42 and got %v", res2.Significance.Seed)
+	}
+}
+
+//=============================================================================
+
+func TestCalcSignificanceWithoutDeviation(t *testing.T) {
+	res := newSignificanceResponse(5, 5, 5)
+
+	calcSignificance(res, 0)
+
+	sig := res.Significance
+	if sig.Computable || sig.Significant || sig.TStatistic != 0 || sig.PValue != 1 {
+		t.Errorf("Bad significance: Expected not computable and not significant and got %+v", *sig)
+	}
+}
+
+//=============================================================================
+
+func newSignificanceResponse(profits ...float64) *AnalysisResponse {
+	tsMap  := map[uint]*db.TradingSystem{ 1: { Id: 1 } }
+	trades := []db.Trade{}
+
+	for i, profit := range profits {
+		trades = append(trades, dbtest.NewTrade(1, db.TradeTypeLong, dbtest.Time(i +1, 10), dbtest.Time(i +1, 15), profit, 1))
+	}
+
+	return &AnalysisResponse{
+		Trades: &trades,
+		pc    : core.NewProfitCalculator(tsMap),
+	}
+}
+
+//=============================================================================
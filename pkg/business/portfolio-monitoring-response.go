@@ -40,19 +40,27 @@ type PortfolioMonitoringParams struct {
 //=============================================================================
 
 type BaseMonitoring struct {
-	Time          *[]time.Time `json:"time"`
-	GrossProfit   *[]float64   `json:"grossProfit"`
-	NetProfit     *[]float64   `json:"netProfit"`
-	GrossDrawdown *[]float64   `json:"grossDrawdown"`
-	NetDrawdown   *[]float64   `json:"netDrawdown"`
+	Time             *[]time.Time `json:"time"`
+	GrossProfit      *[]float64   `json:"grossProfit"`
+	NetProfit        *[]float64   `json:"netProfit"`
+	GrossDrawdown    *[]float64   `json:"grossDrawdown"`
+	NetDrawdown      *[]float64   `json:"netDrawdown"`
+	TotalGrossProfit float64      `json:"totalGrossProfit"`
+	TotalNetProfit   float64      `json:"totalNetProfit"`
+	MaxGrossDrawdown float64      `json:"maxGrossDrawdown"`
+	MaxNetDrawdown   float64      `json:"maxNetDrawdown"`
 }
 
 //=============================================================================
 
+//--- Contribution is the percentage of the portfolio net profit
+
 type TradingSystemMonitoring struct {
 	BaseMonitoring
-	Id   uint   `json:"id"`
-	Name string `json:"name"`
+	Id           uint    `json:"id"`
+	Name         string  `json:"name"`
+	Trades       int     `json:"trades"`
+	Contribution float64 `json:"contribution"`
 }
 
 //=============================================================================
@@ -60,13 +68,24 @@ type TradingSystemMonitoring struct {
 func NewTradingSystemMonitoring(size int) *TradingSystemMonitoring {
 	tsa := &TradingSystemMonitoring{}
 
+	timeSlice   := make([]time.Time, size)
+	grossProfit := make([]float64,   size)
+	netProfit   := make([]float64,   size)
+
+	tsa.Time        = &timeSlice
+	tsa.GrossProfit = &grossProfit
+	tsa.NetProfit   = &netProfit
+
 	return tsa
 }
 
 //=============================================================================
 
+//--- Currency is empty if values are in the currency of each trading system
+
 type PortfolioMonitoringResponse struct {
 	BaseMonitoring
+	Currency       string                     `json:"currency"`
 	TradingSystems []*TradingSystemMonitoring `json:"tradingSystems"`
 }
 
@@ -25,17 +25,19 @@ THE SOFTWARE.
 package business
 
 import (
+	"sort"
+	"time"
+
+	"github.com/tradalia/core/auth"
 	"github.com/tradalia/core/req"
 	"github.com/tradalia/portfolio-trader/pkg/core"
 	"github.com/tradalia/portfolio-trader/pkg/db"
 	"gorm.io/gorm"
-	"sort"
-	"time"
 )
 
 //=============================================================================
 
-func GetPortfolioMonitoring(tx *gorm.DB, params *PortfolioMonitoringParams) (*PortfolioMonitoringResponse, error) {
+func GetPortfolioMonitoring(tx *gorm.DB, c *auth.Context, params *PortfolioMonitoringParams) (*PortfolioMonitoringResponse, error) {
 
 	//--- Get list of trading systems and check length
 
@@ -49,6 +51,11 @@ func GetPortfolioMonitoring(tx *gorm.DB, params *PortfolioMonitoringParams) (*Po
 		return nil, req.NewNotFoundError("Missing some trading systems (input:%v. found:%v)", len(params.TsIds), len(tsMap))
 	}
 
+	err = checkTradingSystemsAccess(c, tsMap)
+	if err != nil {
+		return nil, err
+	}
+
 	//--- Get trading systems daily data
 
 	fromTime := calcFromTime(params.Period)
@@ -59,9 +66,20 @@ func GetPortfolioMonitoring(tx *gorm.DB, params *PortfolioMonitoringParams) (*Po
 		return nil, err
 	}
 
-	trMap := buildSortedMapOfInfo(trades)
-	res   := buildMonitoringResult(trMap, tsMap)
-	buildTotalInfo(res)
+	owner, err := getTradingSystemsOwner(c, tsMap)
+	if err != nil {
+		return nil, err
+	}
+
+	pc, err := core.NewUserProfitCalculator(tx, owner, tsMap)
+	if err != nil {
+		return nil, err
+	}
+
+	trMap := buildSortedMapOfInfo(pc.ConvertTrades(trades))
+	res   := buildMonitoringResult(trMap, tsMap, pc)
+	res.Currency = pc.Currency()
+	buildTotalInfo(res, trMap, pc)
 
 	return res, nil
 }
@@ -72,6 +90,22 @@ func GetPortfolioMonitoring(tx *gorm.DB, params *PortfolioMonitoringParams) (*Po
 //===
 //=============================================================================
 
+func checkTradingSystemsAccess(c *auth.Context, tsMap map[uint]*db.TradingSystem) error {
+	if c.Session.IsAdmin() {
+		return nil
+	}
+
+	for id, ts := range tsMap {
+		if ts.Username != c.Session.Username {
+			return req.NewForbiddenError("Trading system not owned by user: %v", id)
+		}
+	}
+
+	return nil
+}
+
+//=============================================================================
+
 func calcFromTime(period int) time.Time {
 	now := time.Now()
 
@@ -92,17 +126,12 @@ func calcIdsArrayFromSourceIds(tsMap map[uint]*db.TradingSystem) []uint {
 
 //=============================================================================
 
-func buildSortedMapOfInfo(list *[]db.Trade) *map[uint][]*db.Trade {
+func buildSortedMapOfInfo(list *[]db.Trade) map[uint][]*db.Trade {
 	trMap := map[uint][]*db.Trade{}
 
-	for _, trade := range *list {
-		trList, ok := trMap[trade.TradingSystemId]
-
-		if !ok {
-			trList = []*db.Trade{}
-		}
-
-		trMap[trade.TradingSystemId] = append(trList, &trade)
+	for i := range *list {
+		trade := &(*list)[i]
+		trMap[trade.TradingSystemId] = append(trMap[trade.TradingSystemId], trade)
 	}
 
 	for _, list := range trMap {
@@ -111,53 +140,55 @@ func buildSortedMapOfInfo(list *[]db.Trade) *map[uint][]*db.Trade {
 		})
 	}
 
-	return &trMap
+	return trMap
 }
 
 //=============================================================================
+//--- Systems without trades in the period are included, with empty data
 
-func buildMonitoringResult(trMap *map[uint][]*db.Trade, tsMap map[uint]*db.TradingSystem) *PortfolioMonitoringResponse {
-	res := &PortfolioMonitoringResponse{}
-
-	if len(*trMap) != 0 {
-		res.TradingSystems = make([]*TradingSystemMonitoring, len(*trMap))
-	} else {
-		return res
+func buildMonitoringResult(trMap map[uint][]*db.Trade, tsMap map[uint]*db.TradingSystem, pc *core.ProfitCalculator) *PortfolioMonitoringResponse {
+	res := &PortfolioMonitoringResponse{
+		TradingSystems: []*TradingSystemMonitoring{},
 	}
 
-	i := 0
-	for key, list := range *trMap {
-		ts := tsMap[key]
-		res.TradingSystems[i] = buildTradingSystemMonitoring(ts, list)
-		i++
+	for id, ts := range tsMap {
+		res.TradingSystems = append(res.TradingSystems, buildTradingSystemMonitoring(ts, trMap[id], pc))
 	}
 
+	sort.Slice(res.TradingSystems, func(i, j int) bool {
+		return res.TradingSystems[i].Id < res.TradingSystems[j].Id
+	})
+
 	return res
 }
 
 //=============================================================================
 
-func buildTradingSystemMonitoring(ts *db.TradingSystem, list []*db.Trade) *TradingSystemMonitoring {
+func buildTradingSystemMonitoring(ts *db.TradingSystem, list []*db.Trade, pc *core.ProfitCalculator) *TradingSystemMonitoring {
 	tsa := NewTradingSystemMonitoring(len(list))
-	tsa.Id   = ts.Id
-	tsa.Name = ts.Name
+	tsa.Id     = ts.Id
+	tsa.Name   = ts.Name
+	tsa.Trades = len(list)
 
 	currRawProfit := 0.0
 	currNetProfit := 0.0
 
 	//--- build data for a single trading system
 
-	for _, tr := range list {
-		currRawProfit += tr.GrossProfit
-		currNetProfit += tr.GrossProfit - float64(ts.CostPerOperation) * 2
+	for i, tr := range list {
+		currRawProfit += pc.GrossProfit(tr)
+		currNetProfit += pc.NetProfit(tr)
 
-		//tsa.Time[i]        = *tr.ExitDate
-		//tsa.GrossProfit[i] = currRawProfit
-		//tsa.NetProfit[i]   = currNetProfit
+		(*tsa.Time)[i]        = *tr.ExitDate
+		(*tsa.GrossProfit)[i] = currRawProfit
+		(*tsa.NetProfit)[i]   = currNetProfit
 	}
 
-	tsa.GrossDrawdown,_ = core.BuildDrawDown(tsa.GrossProfit)
-	tsa.NetDrawdown,  _ = core.BuildDrawDown(tsa.NetProfit)
+	tsa.GrossDrawdown, tsa.MaxGrossDrawdown = core.BuildDrawDown(tsa.GrossProfit)
+	tsa.NetDrawdown,   tsa.MaxNetDrawdown   = core.BuildDrawDown(tsa.NetProfit)
+
+	tsa.TotalGrossProfit = core.Trunc2d(currRawProfit)
+	tsa.TotalNetProfit   = core.Trunc2d(currNetProfit)
 
 	return tsa
 }
@@ -170,23 +201,27 @@ type TotalInfo struct {
 }
 
 //-----------------------------------------------------------------------------
+//--- Portfolio equities use a common daily timeline. Days are the UTC days of
+//--- the exit dates: the equity of a system is carried forward on the days it
+//--- has no trades, so summing daily profits is equivalent
 
-func buildTotalInfo(pm *PortfolioMonitoringResponse) {
+func buildTotalInfo(pm *PortfolioMonitoringResponse, trMap map[uint][]*db.Trade, pc *core.ProfitCalculator) {
 	timeSum := map[time.Time]*TotalInfo{}
 
 	//--- Collect all days with associated sums
 
-	for _, tsm := range (*pm).TradingSystems {
-		for i, t := range *tsm.Time {
-			ds, ok := timeSum[t]
+	for _, list := range trMap {
+		for _, tr := range list {
+			day := tr.ExitDate.UTC().Truncate(time.Hour * 24)
+			ds, ok := timeSum[day]
 
 			if !ok {
 				ds = &TotalInfo{}
-				timeSum[t] = ds
+				timeSum[day] = ds
 			}
 
-			ds.grossProfit += (*tsm.GrossProfit)[i]
-			ds.netProfit   += (*tsm.NetProfit)[i]
+			ds.grossProfit += pc.GrossProfit(tr)
+			ds.netProfit   += pc.NetProfit(tr)
 		}
 	}
 
@@ -202,24 +237,39 @@ func buildTotalInfo(pm *PortfolioMonitoringResponse) {
 		return res[i].Before(res[j])
 	})
 
-	//pm.Time = res
-
 	//--- Loop on all days and build total arrays
 
-	//pm.GrossProfit   = make([]float64, len(res))
-	//pm.NetProfit     = make([]float64, len(res))
-	//pm.GrossDrawdown = make([]float64, len(res))
-	//pm.NetDrawdown   = make([]float64, len(res))
+	grossProfit := make([]float64, len(res))
+	netProfit   := make([]float64, len(res))
+	currGross   := 0.0
+	currNet     := 0.0
+
+	for i, day := range res {
+		ds := timeSum[day]
+		currGross += ds.grossProfit
+		currNet   += ds.netProfit
+
+		grossProfit[i] = currGross
+		netProfit  [i] = currNet
+	}
+
+	pm.Time        = &res
+	pm.GrossProfit = &grossProfit
+	pm.NetProfit   = &netProfit
+
+	pm.GrossDrawdown, pm.MaxGrossDrawdown = core.BuildDrawDown(pm.GrossProfit)
+	pm.NetDrawdown,   pm.MaxNetDrawdown   = core.BuildDrawDown(pm.NetProfit)
+
+	pm.TotalGrossProfit = core.Trunc2d(currGross)
+	pm.TotalNetProfit   = core.Trunc2d(currNet)
 
-	//for i, day := range res {
-		//ds := timeSum[day]
-		//
-		//pm.GrossProfit[i] = ds.grossProfit
-		//pm.NetProfit[i]   = ds.netProfit
-	//}
+	//--- Contribution of each system to the portfolio net profit
 
-	pm.GrossDrawdown,_ = core.BuildDrawDown(pm.GrossProfit)
-	pm.NetDrawdown,  _ = core.BuildDrawDown(pm.NetProfit)
+	for _, tsm := range pm.TradingSystems {
+		if currNet != 0 {
+			tsm.Contribution = core.Trunc2d(tsm.TotalNetProfit * 100 / currNet)
+		}
+	}
 }
 
 //=============================================================================
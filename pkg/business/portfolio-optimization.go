@@ -0,0 +1,98 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+package business
+
+import (
+	"github.com/tradalia/core/auth"
+	"github.com/tradalia/core/req"
+	"github.com/tradalia/portfolio-trader/pkg/business/optimizer"
+	"github.com/tradalia/portfolio-trader/pkg/core"
+	"github.com/tradalia/portfolio-trader/pkg/db"
+	"gorm.io/gorm"
+)
+
+//=============================================================================
+
+func StartPortfolioOptimization(tx *gorm.DB, c *auth.Context, oreq *optimizer.OptimizationRequest) error {
+	err := oreq.Validate()
+	if err != nil {
+		return err
+	}
+
+	tsMap, err := resolveTradingSystems(tx, c, oreq.TsIds, oreq.PortfolioId)
+	if err != nil {
+		return err
+	}
+
+	for _, ts := range tsMap {
+		if ts.MarginValue <= 0 {
+			return req.NewUnprocessableEntityError("Trading system has no margin value: %v", ts.Name)
+		}
+	}
+
+	returns := &[]db.DailyReturn{}
+	tsIds   := calcIdsArrayFromSourceIds(tsMap)
+
+	if len(tsIds) > 0 {
+		returns, err = db.FindDailyReturnsByTsIdsFromTime(tx, tsIds, calcBackPeriod(oreq.DaysBack), nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(*returns) == 0 {
+		return req.NewUnprocessableEntityError("No daily returns found for the given trading systems and period")
+	}
+
+	owner, err := getTradingSystemsOwner(c, tsMap)
+	if err != nil {
+		return err
+	}
+
+	pc, err := core.NewUserProfitCalculator(tx, owner, tsMap)
+	if err != nil {
+		return err
+	}
+
+	c.Log.Info("StartPortfolioOptimization: Starting optimization", "candidates", len(tsMap), "objective", oreq.Objective, "mode", oreq.Mode)
+	optimizer.StartOptimization(c.Session.Username, tsMap, pc, returns, oreq)
+
+	return nil
+}
+
+//=============================================================================
+
+func StopPortfolioOptimization(c *auth.Context) error {
+	c.Log.Info("StopPortfolioOptimization: Stopping optimization", "username", c.Session.Username)
+	return optimizer.StopOptimization(c.Session.Username)
+}
+
+//=============================================================================
+
+func GetPortfolioOptimizationInfo(c *auth.Context) (*optimizer.OptimizationResponse, error) {
+	info := optimizer.GetOptimizationInfo(c.Session.Username)
+	return optimizer.NewOptimizationResponse(info), nil
+}
+
+//=============================================================================
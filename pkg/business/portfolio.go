@@ -25,15 +25,46 @@ THE SOFTWARE.
 package business
 
 import (
+	"log/slog"
+	"sort"
+	"strings"
+
 	"github.com/tradalia/core/auth"
+	"github.com/tradalia/core/msg"
 	"github.com/tradalia/core/req"
+	"github.com/tradalia/portfolio-trader/pkg/core"
 	"github.com/tradalia/portfolio-trader/pkg/db"
 	"gorm.io/gorm"
-	"log/slog"
 )
 
 //=============================================================================
 
+const UnassignedPortfolioName = "Unassigned"
+
+//=============================================================================
+
+type PortfolioRequest struct {
+	ParentId uint   `json:"parentId"`
+	Name     string `json:"name"`
+	Position int    `json:"position"`
+}
+
+//=============================================================================
+
+type TradingSystemPortfolioRequest struct {
+	PortfolioId *uint `json:"portfolioId"`
+}
+
+//=============================================================================
+
+type PortfolioAssignmentMessage struct {
+	TradingSystemId uint   `json:"tradingSystemId"`
+	PortfolioId     *uint  `json:"portfolioId"`
+	Username        string `json:"username"`
+}
+
+//=============================================================================
+
 func GetPortfolios(tx *gorm.DB, c *auth.Context, filter map[string]any, offset int, limit int) (*[]db.Portfolio, error) {
 	if ! c.Session.IsAdmin() {
 		filter["username"] = c.Session.Username
@@ -65,12 +96,329 @@ func GetPortfolioTree(tx *gorm.DB, c *auth.Context, filter map[string]any, offse
 	return buildPortfolioTree(c.Log, poList, tsList), nil
 }
 
+//=============================================================================
+
+func AddPortfolio(tx *gorm.DB, c *auth.Context, pr *PortfolioRequest) (*db.Portfolio, error) {
+	c.Log.Info("AddPortfolio: Adding a new portfolio", "name", pr.Name, "parentId", pr.ParentId)
+
+	name := strings.TrimSpace(pr.Name)
+	if name == "" {
+		return nil, req.NewBadRequestError("Portfolio name is required")
+	}
+
+	//--- A child portfolio belongs to the owner of its parent (admins can add
+	//--- portfolios to other users' trees)
+
+	username := c.Session.Username
+
+	if pr.ParentId != 0 {
+		parent, err := getPortfolioAndCheckAccess(tx, c, pr.ParentId)
+		if err != nil {
+			return nil, err
+		}
+
+		username = parent.Username
+	}
+
+	p := &db.Portfolio{
+		ParentId: pr.ParentId,
+		Username: username,
+		Name    : name,
+		Position: pr.Position,
+	}
+
+	err := db.AddPortfolio(tx, p)
+	if err != nil {
+		return nil, req.NewServerErrorByError(err)
+	}
+
+	c.Log.Info("AddPortfolio: Portfolio added", "id", p.Id)
+	return p, nil
+}
+
+//=============================================================================
+
+func UpdatePortfolio(tx *gorm.DB, c *auth.Context, id uint, pr *PortfolioRequest) (*db.Portfolio, error) {
+	c.Log.Info("UpdatePortfolio: Updating portfolio", "id", id, "name", pr.Name, "parentId", pr.ParentId)
+
+	p, err := getPortfolioAndCheckAccess(tx, c, id)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(pr.Name)
+	if name == "" {
+		return nil, req.NewBadRequestError("Portfolio name is required")
+	}
+
+	if pr.ParentId != p.ParentId && pr.ParentId != 0 {
+		err = checkNewParent(tx, c, p, pr.ParentId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	p.ParentId = pr.ParentId
+	p.Name     = name
+	p.Position = pr.Position
+
+	err = db.UpdatePortfolio(tx, p)
+	if err != nil {
+		return nil, req.NewServerErrorByError(err)
+	}
+
+	c.Log.Info("UpdatePortfolio: Portfolio updated", "id", id)
+	return p, nil
+}
+
+//=============================================================================
+
+func DeletePortfolio(tx *gorm.DB, c *auth.Context, id uint) (*db.Portfolio, error) {
+	c.Log.Info("DeletePortfolio: Deleting portfolio", "id", id)
+
+	p, err := getPortfolioAndCheckAccess(tx, c, id)
+	if err != nil {
+		return nil, err
+	}
+
+	children, err := db.CountPortfolioChildren(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	systems, err := db.CountPortfolioTradingSystems(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if children != 0 || systems != 0 {
+		return nil, req.NewUnprocessableEntityError("Portfolio is not empty (portfolios:%v, trading systems:%v)", children, systems)
+	}
+
+	err = db.DeleteRiskLimit(tx, id)
+	if err != nil {
+		return nil, req.NewServerErrorByError(err)
+	}
+
+	err = db.DeletePortfolio(tx, id)
+	if err != nil {
+		return nil, req.NewServerErrorByError(err)
+	}
+
+	c.Log.Info("DeletePortfolio: Portfolio deleted", "id", id)
+	return p, nil
+}
+
+//=============================================================================
+//--- A nil portfolio id moves the trading system into the unassigned bucket
+
+func SetTradingSystemPortfolio(tx *gorm.DB, c *auth.Context, tsId uint, pr *TradingSystemPortfolioRequest) (*TradingSystemPropertyResponse, error) {
+	c.Log.Info("SetTradingSystemPortfolio: Portfolio change request", "id", tsId, "portfolioId", pr.PortfolioId)
+
+	ts, err := getTradingSystemAndCheckAccess(tx, c, tsId)
+	if err != nil {
+		return nil, err
+	}
+
+	if pr.PortfolioId != nil && *pr.PortfolioId == 0 {
+		pr.PortfolioId = nil
+	}
+
+	if samePortfolio(ts.PortfolioId, pr.PortfolioId) {
+		return &TradingSystemPropertyResponse{
+			Status : ResponseStatusSkipped,
+		}, nil
+	}
+
+	if pr.PortfolioId != nil {
+		p, err := getPortfolioAndCheckAccess(tx, c, *pr.PortfolioId)
+		if err != nil {
+			return nil, err
+		}
+
+		if p.Username != ts.Username {
+			return nil, req.NewForbiddenError("Portfolio and trading system belong to different users")
+		}
+	}
+
+	ts.PortfolioId = pr.PortfolioId
+	err = db.UpdateTradingSystem(tx, ts)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Log.Info("SetTradingSystemPortfolio: Portfolio changed", "id", tsId, "portfolioId", pr.PortfolioId)
+
+	return &TradingSystemPropertyResponse{
+		Status       : ResponseStatusOk,
+		TradingSystem: ts,
+	}, nil
+}
+
+//=============================================================================
+//--- Messages are sent after the transaction has been committed, so receivers
+//--- never see changes that are rolled back. As the change cannot be undone
+//--- anymore, a failure is only logged
+
+func SendPortfolioMessage(c *auth.Context, msgType int, p *db.Portfolio) {
+	err := msg.SendMessage(msg.ExInventory, msg.SourcePortfolio, msgType, p)
+	if err != nil {
+		c.Log.Error("SendPortfolioMessage: Cannot send portfolio message", "id", p.Id, "type", msgType, "error", err.Error())
+	}
+}
+
+//=============================================================================
+
+func SendPortfolioAssignmentMessage(c *auth.Context, ts *db.TradingSystem) {
+	err := msg.SendMessage(msg.ExInventory, msg.SourcePortfolio, msg.TypeChange, &PortfolioAssignmentMessage{
+		TradingSystemId: ts.Id,
+		PortfolioId    : ts.PortfolioId,
+		Username       : ts.Username,
+	})
+	if err != nil {
+		c.Log.Error("SendPortfolioAssignmentMessage: Cannot send assignment message", "id", ts.Id, "error", err.Error())
+	}
+}
+
 //=============================================================================
 //===
 //=== Private methods
 //===
 //=============================================================================
 
+func getPortfolioAndCheckAccess(tx *gorm.DB, c *auth.Context, id uint) (*db.Portfolio, error) {
+	p, err := db.GetPortfolioById(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if p == nil {
+		return nil, req.NewNotFoundError("Missing portfolio with id=%v", id)
+	}
+
+	if ! c.Session.IsAdmin() {
+		if p.Username != c.Session.Username {
+			return nil, req.NewForbiddenError("Portfolio not owned by user: %v", id)
+		}
+	}
+
+	return p, nil
+}
+
+//=============================================================================
+//--- Trading systems can be selected by ids or by portfolio (with all its
+//--- descendants), but not both
+
+func resolveTradingSystems(tx *gorm.DB, c *auth.Context, tsIds []uint, portfolioId uint) (map[uint]*db.TradingSystem, error) {
+	if len(tsIds) > 0 && portfolioId != 0 {
+		return nil, req.NewBadRequestError("Provide either a list of trading systems or a portfolio")
+	}
+
+	if portfolioId != 0 {
+		_, tsMap, err := getPortfolioTradingSystems(tx, c, portfolioId)
+		return tsMap, err
+	}
+
+	if len(tsIds) == 0 {
+		return nil, req.NewBadRequestError("Provide either a list of trading systems or a portfolio")
+	}
+
+	tsMap, err := db.GetTradingSystemsByIdsAsMap(tx, tsIds)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range tsIds {
+		if _, ok := tsMap[id]; !ok {
+			return nil, req.NewNotFoundError("Missing trading system with id=%v", id)
+		}
+	}
+
+	err = checkTradingSystemsAccess(c, tsMap)
+	if err != nil {
+		return nil, err
+	}
+
+	return tsMap, nil
+}
+
+//=============================================================================
+//--- Returns the trading systems of the portfolio and of all its descendants
+
+func getPortfolioTradingSystems(tx *gorm.DB, c *auth.Context, id uint) (*db.Portfolio, map[uint]*db.TradingSystem, error) {
+	p, err := getPortfolioAndCheckAccess(tx, c, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tsMap, err := core.GetPortfolioTradingSystems(tx, p)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return p, tsMap, nil
+}
+
+//=============================================================================
+//--- Walks up from the new parent: reaching the portfolio itself means that
+//--- the new parent is one of its descendants
+
+func checkNewParent(tx *gorm.DB, c *auth.Context, p *db.Portfolio, parentId uint) error {
+	parent, err := getPortfolioAndCheckAccess(tx, c, parentId)
+	if err != nil {
+		return err
+	}
+
+	if parent.Username != p.Username {
+		return req.NewForbiddenError("Parent portfolio belongs to a different user")
+	}
+
+	list, err := db.GetPortfoliosByUser(tx, p.Username)
+	if err != nil {
+		return err
+	}
+
+	poMap := map[uint]*db.Portfolio{}
+	for i := range *list {
+		poMap[(*list)[i].Id] = &(*list)[i]
+	}
+
+	visited := map[uint]bool{}
+
+	for id := parentId; id != 0; {
+		if id == p.Id {
+			return req.NewUnprocessableEntityError("Cannot move portfolio %v under one of its children", p.Id)
+		}
+
+		if visited[id] {
+			return req.NewServerError("Portfolios have circular loops for user %v", p.Username)
+		}
+
+		visited[id] = true
+
+		curr, ok := poMap[id]
+		if !ok {
+			break
+		}
+
+		id = curr.ParentId
+	}
+
+	return nil
+}
+
+//=============================================================================
+
+func samePortfolio(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
+
+//=============================================================================
+
 func buildPortfolioTree(log *slog.Logger, poList *[]db.Portfolio, tsList *[]db.TradingSystem) *[]*PortfolioTree {
 
 	//--- Step 1: Collect all nodes into a map
@@ -88,25 +436,43 @@ func buildPortfolioTree(log *slog.Logger, poList *[]db.Portfolio, tsList *[]db.T
 		fullMap[p.Id] = pt
 	}
 
-	//--- Step 2: Build the tree
+	//--- Step 2: Build the tree. Portfolios with a missing parent become roots
 
 	for key, p := range fullMap {
 		if p.ParentId != 0 {
-			parent := fullMap[p.ParentId]
-			parent.AddChild(p)
-			delete(nodeMap, key)
+			parent, ok := fullMap[p.ParentId]
+			if ok {
+				parent.AddChild(p)
+				delete(nodeMap, key)
+			}
 		}
 	}
 
-	//--- Step 2: Add trading system information
+	//--- Step 3: Add trading system information. Systems without a (visible)
+	//--- portfolio go into the unassigned bucket
 
-	for _, ts := range *tsList {
-		aux := ts
-		portfolio := fullMap[*ts.PortfolioId]
-		portfolio.AddTradingSystem(&aux)
+	unassigned := &PortfolioTree{
+		Portfolio:      db.Portfolio{ Name: UnassignedPortfolioName },
+		Children:       []*PortfolioTree{},
+		TradingSystems: []*db.TradingSystem{},
 	}
 
-	//--- Step 3: Return tree
+	for i := range *tsList {
+		ts := &(*tsList)[i]
+		var portfolio *PortfolioTree
+
+		if ts.PortfolioId != nil {
+			portfolio = fullMap[*ts.PortfolioId]
+		}
+
+		if portfolio == nil {
+			portfolio = unassigned
+		}
+
+		portfolio.AddTradingSystem(ts)
+	}
+
+	//--- Step 4: Return tree
 
 	if len(*poList) > 0 && len(nodeMap) == 0 {
 		log.Error("Portfolios have circular loops (!)")
@@ -118,7 +484,29 @@ func buildPortfolioTree(log *slog.Logger, poList *[]db.Portfolio, tsList *[]db.T
 		result = append(result, p)
 	}
 
+	for _, p := range fullMap {
+		sortPortfolioTrees(p.Children)
+	}
+
+	sortPortfolioTrees(result)
+
+	if len(unassigned.TradingSystems) > 0 {
+		result = append(result, unassigned)
+	}
+
 	return &result
 }
 
 //=============================================================================
+
+func sortPortfolioTrees(list []*PortfolioTree) {
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].Position != list[j].Position {
+			return list[i].Position < list[j].Position
+		}
+
+		return list[i].Name < list[j].Name
+	})
+}
+
+//=============================================================================
@@ -0,0 +1,206 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+
+
+package business
+
+import (
+	"time"
+
+	"github.com/tradalia/core/auth"
+	"github.com/tradalia/core/datatype"
+	"github.com/tradalia/core/req"
+	"github.com/tradalia/portfolio-trader/pkg/business/risk"
+	"github.com/tradalia/portfolio-trader/pkg/db"
+	"gorm.io/gorm"
+)
+
+//=============================================================================
+
+func GetRiskLimit(tx *gorm.DB, c *auth.Context, id uint) (*risk.LimitResponse, error) {
+	_, err := getPortfolioAndCheckAccess(tx, c, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rl, err := db.GetRiskLimitByPortfolioId(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if rl == nil {
+		return nil, req.NewNotFoundError("Missing risk limit for portfolio with id=%v", id)
+	}
+
+	st, err := risk.Evaluate(tx, rl)
+	if err != nil {
+		return nil, err
+	}
+
+	return &risk.LimitResponse{
+		Limit : rl,
+		Status: st,
+	}, nil
+}
+
+//=============================================================================
+//--- The limits are checked immediately, so a breach takes effect without
+//--- waiting for new trades or for the status updater
+
+func SetRiskLimit(tx *gorm.DB, c *auth.Context, id uint, lr *risk.LimitRequest) (*risk.LimitResponse, error) {
+	c.Log.Info("SetRiskLimit: Setting risk limit", "portfolioId", id)
+
+	err := lr.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	p, tsMap, err := getPortfolioTradingSystems(tx, c, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tsId := range lr.TsIds {
+		if _, ok := tsMap[tsId]; !ok {
+			return nil, req.NewBadRequestError("Trading system does not belong to the portfolio: %v", tsId)
+		}
+	}
+
+	rl, err := db.GetRiskLimitByPortfolioId(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if rl == nil {
+		rl = &db.PortfolioRiskLimit{
+			PortfolioId: id,
+			Username   : p.Username,
+			StartDate  : datatype.Today(time.UTC),
+		}
+	}
+
+	if !lr.StartDate.IsNil() {
+		rl.StartDate = lr.StartDate
+	}
+
+	rl.Enabled          = lr.Enabled
+	rl.MaxDrawdown      = lr.MaxDrawdown
+	rl.MaxDailyLoss     = lr.MaxDailyLoss
+	rl.MaxActiveSystems = lr.MaxActiveSystems
+	rl.MaxMargin        = lr.MaxMargin
+	rl.Action           = lr.Action
+	rl.TsIds            = lr.TsIds
+
+	var st *risk.Status
+
+	if rl.Enabled {
+		st, err = risk.CheckPortfolio(tx, rl)
+	} else {
+		rl.Breached       = false
+		rl.BreachedLimits = ""
+
+		err = db.SetRiskLimit(tx, rl)
+		if err == nil {
+			st, err = risk.Evaluate(tx, rl)
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	c.Log.Info("SetRiskLimit: Risk limit set", "portfolioId", id, "enabled", rl.Enabled, "breached", rl.Breached)
+
+	return &risk.LimitResponse{
+		Limit : rl,
+		Status: st,
+	}, nil
+}
+
+//=============================================================================
+
+func AcknowledgeRiskLimit(tx *gorm.DB, c *auth.Context, id uint) (*risk.LimitResponse, error) {
+	c.Log.Info("AcknowledgeRiskLimit: Acknowledging risk limit breaches", "portfolioId", id)
+
+	_, err := getPortfolioAndCheckAccess(tx, c, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rl, err := db.GetRiskLimitByPortfolioId(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if rl == nil {
+		return nil, req.NewNotFoundError("Missing risk limit for portfolio with id=%v", id)
+	}
+
+	st, err := risk.Acknowledge(tx, rl)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Log.Info("AcknowledgeRiskLimit: Risk limit breaches acknowledged", "portfolioId", id)
+
+	return &risk.LimitResponse{
+		Limit : rl,
+		Status: st,
+	}, nil
+}
+
+//=============================================================================
+
+func DeleteRiskLimit(tx *gorm.DB, c *auth.Context, id uint) error {
+	c.Log.Info("DeleteRiskLimit: Deleting risk limit", "portfolioId", id)
+
+	_, err := getPortfolioAndCheckAccess(tx, c, id)
+	if err != nil {
+		return err
+	}
+
+	return db.DeleteRiskLimit(tx, id)
+}
+
+//=============================================================================
+
+func GetRiskEvents(tx *gorm.DB, c *auth.Context, id uint, offset int, limit int) (*[]db.RiskEvent, error) {
+	_, err := getPortfolioAndCheckAccess(tx, c, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return db.GetRiskEventsByPortfolioId(tx, id, offset, limit)
+}
+
+//=============================================================================
+//--- The event of the check is sent after the transaction has been committed
+
+func SendRiskEvent(res *risk.LimitResponse) {
+	if res.Status != nil {
+		risk.SendEvents(res.Status.Event)
+	}
+}
+
+//=============================================================================
@@ -0,0 +1,79 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+
+
+package risk
+
+import (
+	"github.com/tradalia/core/datatype"
+	"github.com/tradalia/core/req"
+)
+
+//=============================================================================
+
+const (
+	ActionDeactivate = "deactivate"
+	ActionFlag       = "flag"
+)
+
+//=============================================================================
+//--- Limits set to 0 are disabled. If TsIds is empty, the action is applied to
+//--- all trading systems of the portfolio. Drawdown and daily loss are measured
+//--- on daily returns from StartDate. If omitted, the creation date of the limit
+//--- is used
+
+type LimitRequest struct {
+	Enabled          bool             `json:"enabled"`
+	StartDate        datatype.IntDate `json:"startDate"`
+	MaxDrawdown      float64          `json:"maxDrawdown"`
+	MaxDailyLoss     float64          `json:"maxDailyLoss"`
+	MaxActiveSystems int              `json:"maxActiveSystems"`
+	MaxMargin        float64          `json:"maxMargin"`
+	Action           string           `json:"action"`
+	TsIds            []uint           `json:"tsIds"`
+}
+
+//=============================================================================
+
+func (r *LimitRequest) Validate() error {
+	if r.MaxDrawdown < 0 || r.MaxDailyLoss < 0 || r.MaxActiveSystems < 0 || r.MaxMargin < 0 {
+		return req.NewBadRequestError("Limits cannot be negative")
+	}
+
+	if r.StartDate != 0 && !r.StartDate.IsValid() {
+		return req.NewBadRequestError("Invalid start date: %v", r.StartDate)
+	}
+
+	if r.Action == "" {
+		r.Action = ActionFlag
+	}
+
+	if r.Action != ActionDeactivate && r.Action != ActionFlag {
+		return req.NewBadRequestError("Invalid action: %v", r.Action)
+	}
+
+	return nil
+}
+
+//=============================================================================
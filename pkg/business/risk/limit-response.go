@@ -0,0 +1,73 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+
+
+package risk
+
+import (
+	"github.com/tradalia/portfolio-trader/pkg/db"
+)
+
+//=============================================================================
+
+const (
+	LimitMaxDrawdown      = "maxDrawdown"
+	LimitMaxDailyLoss     = "maxDailyLoss"
+	LimitMaxActiveSystems = "maxActiveSystems"
+	LimitMaxMargin        = "maxMargin"
+)
+
+//=============================================================================
+
+type LimitResponse struct {
+	Limit   *db.PortfolioRiskLimit `json:"limit"`
+	Status  *Status                `json:"status"`
+}
+
+//=============================================================================
+//--- Current values of the portfolio, in Currency if the user has a base one.
+//--- TsIds are the trading systems that have been deactivated or flagged by
+//--- the last check. Event is the notification recorded by the check, that the
+//--- caller must send once the transaction has been committed
+
+type Status struct {
+	Currency       string    `json:"currency"`
+	Drawdown       float64   `json:"drawdown"`
+	DailyLoss      float64   `json:"dailyLoss"`
+	ActiveSystems  int       `json:"activeSystems"`
+	Margin         float64   `json:"margin"`
+	Breaches       []*Breach `json:"breaches"`
+	TsIds          []uint    `json:"tsIds"`
+	Event          *Event    `json:"-"`
+}
+
+//=============================================================================
+
+type Breach struct {
+	Limit     string  `json:"limit"`
+	Value     float64 `json:"value"`
+	Threshold float64 `json:"threshold"`
+}
+
+//=============================================================================
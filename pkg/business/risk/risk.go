@@ -0,0 +1,497 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+
+
+package risk
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tradalia/core/datatype"
+	"github.com/tradalia/core/msg"
+	"github.com/tradalia/core/req"
+	"github.com/tradalia/portfolio-trader/pkg/core"
+	"github.com/tradalia/portfolio-trader/pkg/db"
+	"gorm.io/gorm"
+)
+
+//=============================================================================
+//--- Checks the limits of all portfolios that contain the trading system, from
+//--- its own portfolio up to the root. A failing check (like missing FX rates)
+//--- is logged and does not stop the other ones. The recorded events must be
+//--- sent after the transaction has been committed
+
+func CheckTradingSystem(tx *gorm.DB, ts *db.TradingSystem) ([]*Event, error) {
+	if ts.PortfolioId == nil {
+		return nil, nil
+	}
+
+	list, err := db.GetPortfoliosByUser(tx, ts.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	limits, err := db.GetRiskLimitsByPortfolioIds(tx, core.GetPortfolioAncestorIds(list, *ts.PortfolioId))
+	if err != nil {
+		return nil, err
+	}
+
+	var events []*Event
+
+	for i := range *limits {
+		rl := &(*limits)[i]
+
+		st, err := CheckPortfolio(tx, rl)
+		if err != nil {
+			slog.Error("CheckTradingSystem: Cannot check risk limits of portfolio", "portfolioId", rl.PortfolioId, "tsId", ts.Id, "error", err)
+		} else if st != nil && st.Event != nil {
+			events = append(events, st.Event)
+		}
+	}
+
+	return events, nil
+}
+
+//=============================================================================
+//--- Evaluates the limits and, while any of them is breached, deactivates or
+//--- flags the trading systems. Breaches are latched until the user acknowledges
+//--- them and the action is applied on every check, so that systems turned on
+//--- again (manually or by the trading filters) are stopped again. An event is
+//--- recorded when a new limit is breached and returned in the status, to be
+//--- sent after the transaction has been committed
+
+func CheckPortfolio(tx *gorm.DB, rl *db.PortfolioRiskLimit) (*Status, error) {
+	p, tsMap, err := getPortfolioTradingSystems(tx, rl)
+	if err != nil || p == nil {
+		return nil, err
+	}
+
+	st, pc, err := evaluate(tx, rl, tsMap)
+	if err != nil {
+		return nil, err
+	}
+
+	limits := mergeLimits(rl.BreachedLimits, breachedLimits(st))
+
+	if limits != "" {
+		st.TsIds, err = applyAction(tx, rl, selectTargets(rl, st, limits, tsMap, pc))
+		if err != nil {
+			return nil, err
+		}
+
+		if len(st.TsIds) > 0 {
+			slog.Warn("CheckPortfolio: Risk limits breached. Action applied", "portfolioId", p.Id, "action", rl.Action, "tsIds", st.TsIds)
+		}
+	}
+
+	now := time.Now()
+
+	if limits != rl.BreachedLimits {
+		st.Event, err = recordEvent(tx, p, rl, st, limits, now)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rl.Breached       = limits != ""
+	rl.BreachedLimits = limits
+	rl.LastCheck      = &now
+
+	return st, db.SetRiskLimit(tx, rl)
+}
+
+//=============================================================================
+//--- Releases the latched breaches. This is not possible while some limit is
+//--- still breached, as the next check would latch it again
+
+func Acknowledge(tx *gorm.DB, rl *db.PortfolioRiskLimit) (*Status, error) {
+	p, tsMap, err := getPortfolioTradingSystems(tx, rl)
+	if err != nil || p == nil {
+		return nil, err
+	}
+
+	st, _, err := evaluate(tx, rl, tsMap)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(st.Breaches) > 0 {
+		return nil, req.NewUnprocessableEntityError("Risk limits are still breached: %v", breachedLimits(st))
+	}
+
+	now := time.Now()
+
+	if rl.BreachedLimits != "" {
+		st.Event, err = recordEvent(tx, p, rl, st, "", now)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rl.Breached       = false
+	rl.BreachedLimits = ""
+	rl.LastCheck      = &now
+
+	return st, db.SetRiskLimit(tx, rl)
+}
+
+//=============================================================================
+//--- Evaluates the limits without taking any action
+
+func Evaluate(tx *gorm.DB, rl *db.PortfolioRiskLimit) (*Status, error) {
+	p, tsMap, err := getPortfolioTradingSystems(tx, rl)
+	if err != nil || p == nil {
+		return nil, err
+	}
+
+	st, _, err := evaluate(tx, rl, tsMap)
+	return st, err
+}
+
+//=============================================================================
+//===
+//=== Private functions
+//===
+//=============================================================================
+
+func getPortfolioTradingSystems(tx *gorm.DB, rl *db.PortfolioRiskLimit) (*db.Portfolio, map[uint]*db.TradingSystem, error) {
+	p, err := db.GetPortfolioById(tx, rl.PortfolioId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if p == nil {
+		slog.Warn("CheckPortfolio: Portfolio not found. Skipping risk limits", "portfolioId", rl.PortfolioId)
+		return nil, nil, nil
+	}
+
+	tsMap, err := core.GetPortfolioTradingSystems(tx, p)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return p, tsMap, nil
+}
+
+//=============================================================================
+
+func evaluate(tx *gorm.DB, rl *db.PortfolioRiskLimit, tsMap map[uint]*db.TradingSystem) (*Status, *core.ProfitCalculator, error) {
+	st := &Status{
+		Breaches: []*Breach{},
+		TsIds   : []uint{},
+	}
+
+	//--- Values are in the base currency of the user (if set)
+
+	pc, err := core.NewUserProfitCalculator(tx, rl.Username, tsMap)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	st.Currency = pc.Currency()
+
+	//--- Drawdown and daily loss
+
+	if len(tsMap) > 0 {
+		from := rl.StartDate.ToDateTime(false, time.UTC)
+
+		returns, err := db.FindDailyReturnsByTsIdsFromTime(tx, getTradingSystemIds(tsMap), &from, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		days, values := core.BuildNetDailyReturns(pc.ConvertDailyReturns(returns), pc)
+		st.Drawdown, st.DailyLoss = calcDrawdownAndLoss(days, values, datatype.Today(time.UTC))
+	}
+
+	//--- Active systems and margin in use
+
+	for _, ts := range tsMap {
+		if ts.Running && ts.Active {
+			st.ActiveSystems++
+			st.Margin += pc.Margin(ts.Id)
+		}
+	}
+
+	addBreach(st, LimitMaxDrawdown,      st.Drawdown,               rl.MaxDrawdown)
+	addBreach(st, LimitMaxDailyLoss,     st.DailyLoss,              rl.MaxDailyLoss)
+	addBreach(st, LimitMaxActiveSystems, float64(st.ActiveSystems), float64(rl.MaxActiveSystems))
+	addBreach(st, LimitMaxMargin,        st.Margin,                 rl.MaxMargin)
+
+	return st, pc, nil
+}
+
+//=============================================================================
+
+func getTradingSystemIds(tsMap map[uint]*db.TradingSystem) []uint {
+	var ids []uint
+	for id := range tsMap {
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+//=============================================================================
+//--- Returns the current drawdown (from the equity peak to the last day) and
+//--- the loss of today. A day without returns has no loss
+
+func calcDrawdownAndLoss(days []datatype.IntDate, returns []float64, today datatype.IntDate) (float64, float64) {
+	equity := 0.0
+	peak   := 0.0
+	loss   := 0.0
+
+	for i, r := range returns {
+		equity += r
+		peak    = max(peak, equity)
+
+		if days[i] == today {
+			loss = max(0, -r)
+		}
+	}
+
+	return peak - equity, loss
+}
+
+//=============================================================================
+
+func addBreach(st *Status, limit string, value float64, threshold float64) {
+	if threshold > 0 && value > threshold {
+		st.Breaches = append(st.Breaches, &Breach{
+			Limit    : limit,
+			Value    : value,
+			Threshold: threshold,
+		})
+	}
+}
+
+//=============================================================================
+//--- While the drawdown or the daily loss is latched, all systems are targeted.
+//--- Otherwise, only the ones needed to go back within the number of active
+//--- systems and the margin, starting from the highest margin
+
+func selectTargets(rl *db.PortfolioRiskLimit, st *Status, limits string, tsMap map[uint]*db.TradingSystem, pc *core.ProfitCalculator) []*db.TradingSystem {
+	var list []*db.TradingSystem
+
+	for _, ts := range tsMap {
+		if len(rl.TsIds) > 0 && !slices.Contains(rl.TsIds, ts.Id) {
+			continue
+		}
+
+		if ts.Running && ts.Active {
+			list = append(list, ts)
+		}
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		mi := pc.Margin(list[i].Id)
+		mj := pc.Margin(list[j].Id)
+
+		if mi != mj {
+			return mi > mj
+		}
+
+		return list[i].Id < list[j].Id
+	})
+
+	latched := strings.Split(limits, ",")
+	if slices.Contains(latched, LimitMaxDrawdown) || slices.Contains(latched, LimitMaxDailyLoss) {
+		return list
+	}
+
+	excessSystems := 0
+	if rl.MaxActiveSystems > 0 {
+		excessSystems = st.ActiveSystems - rl.MaxActiveSystems
+	}
+
+	excessMargin := 0.0
+	if rl.MaxMargin > 0 {
+		excessMargin = st.Margin - rl.MaxMargin
+	}
+
+	var targets []*db.TradingSystem
+
+	for _, ts := range list {
+		if excessSystems <= 0 && excessMargin <= 0 {
+			break
+		}
+
+		targets       = append(targets, ts)
+		excessSystems--
+		excessMargin -= pc.Margin(ts.Id)
+	}
+
+	return targets
+}
+
+//=============================================================================
+//--- Returns the ids of the systems that have been changed
+
+func applyAction(tx *gorm.DB, rl *db.PortfolioRiskLimit, targets []*db.TradingSystem) ([]uint, error) {
+	ids := []uint{}
+
+	for _, ts := range targets {
+		if rl.Action == ActionDeactivate {
+			ts.Active          = false
+			ts.Status          = db.TsStatusPaused
+			ts.SuggestedAction = db.TsActionNone
+		} else if ts.SuggestedAction != db.TsActionTurnOff {
+			ts.SuggestedAction = db.TsActionTurnOff
+		} else {
+			continue
+		}
+
+		err := db.UpdateTradingSystem(tx, ts)
+		if err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, ts.Id)
+	}
+
+	slices.Sort(ids)
+
+	return ids, nil
+}
+
+//=============================================================================
+
+func breachedLimits(st *Status) string {
+	var list []string
+	for _, b := range st.Breaches {
+		list = append(list, b.Limit)
+	}
+
+	return strings.Join(list, ",")
+}
+
+//=============================================================================
+//--- Union of the latched and of the current limits, in a fixed order
+
+func mergeLimits(latched, current string) string {
+	set := strings.Split(latched +","+ current, ",")
+
+	var list []string
+	for _, limit := range []string{ LimitMaxDrawdown, LimitMaxDailyLoss, LimitMaxActiveSystems, LimitMaxMargin } {
+		if slices.Contains(set, limit) {
+			list = append(list, limit)
+		}
+	}
+
+	return strings.Join(list, ",")
+}
+
+//=============================================================================
+
+func recordEvent(tx *gorm.DB, p *db.Portfolio, rl *db.PortfolioRiskLimit, st *Status, limits string, now time.Time) (*Event, error) {
+	e := &db.RiskEvent{
+		PortfolioId: p.Id,
+		Username   : p.Username,
+		EventTime  : now,
+		Type       : db.RiskEventTypeBreach,
+		Limits     : limits,
+		Reason     : buildReason(st),
+		Action     : rl.Action,
+		TsIds      : st.TsIds,
+	}
+
+	level := msg.EventLevel(msg.EventLevelWarning)
+	title := "Portfolio risk limits breached: "+ p.Name
+
+	if limits == "" {
+		e.Type   = db.RiskEventTypeClear
+		e.Reason = "Breaches acknowledged. All limits are within range"
+		e.Action = ""
+		level    = msg.EventLevelInfo
+		title    = "Portfolio risk limits back within range: "+ p.Name
+	}
+
+	err := db.AddRiskEvent(tx, e)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("CheckPortfolio: Risk event recorded", "portfolioId", p.Id, "type", e.Type, "limits", limits)
+
+	return &Event{
+		portfolioId: p.Id,
+		username   : p.Username,
+		level      : level,
+		title      : title,
+		message    : e.Reason,
+		params     : map[string]any{
+			"portfolioId": p.Id,
+			"limits"     : limits,
+			"action"     : e.Action,
+			"tsIds"      : e.TsIds,
+		},
+	}, nil
+}
+
+//=============================================================================
+//--- Notification of a recorded risk event. It is sent only after the
+//--- transaction has been committed, so users are never notified of events
+//--- that are rolled back. A failure is only logged
+
+type Event struct {
+	portfolioId uint
+	username    string
+	level       msg.EventLevel
+	title       string
+	message     string
+	params      map[string]any
+}
+
+//-----------------------------------------------------------------------------
+
+func SendEvents(events ...*Event) {
+	for _, e := range events {
+		if e == nil {
+			continue
+		}
+
+		err := msg.SendEvent(e.username, e.level, e.title, e.message, e.params)
+		if err != nil {
+			slog.Error("SendEvents: Cannot send risk event", "portfolioId", e.portfolioId, "error", err)
+		}
+	}
+}
+
+//=============================================================================
+
+func buildReason(st *Status) string {
+	var list []string
+	for _, b := range st.Breaches {
+		list = append(list, fmt.Sprintf("%s: %.2f exceeds %.2f", b.Limit, b.Value, b.Threshold))
+	}
+
+	return strings.Join(list, "; ")
+}
+
+//=============================================================================
@@ -0,0 +1,149 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+
+
+package risk
+
+import (
+	"testing"
+
+	"github.com/tradalia/core/datatype"
+	"github.com/tradalia/portfolio-trader/pkg/core"
+	"github.com/tradalia/portfolio-trader/pkg/db"
+)
+
+//=============================================================================
+
+func TestCalcDrawdownAndLoss(t *testing.T) {
+	days := []datatype.IntDate{ 20250106, 20250107, 20250108, 20250109 }
+
+	cases := []struct {
+		returns   []float64
+		today     datatype.IntDate
+		drawdown  float64
+		dailyLoss float64
+	}{
+		{ []float64{ 100, -50,  20, -30 }, 20250109, 60,  30 },
+		{ []float64{ 100, -50,  20, -30 }, 20250110, 60,   0 },
+		{ []float64{ 100, -50,  20,  10 }, 20250109, 20,   0 },
+		{ []float64{ -10, -20,  40, -5  }, 20250109, 5,    5 },
+		{ []float64{},                     20250109, 0,    0 },
+	}
+
+	for _, c := range cases {
+		drawdown, dailyLoss := calcDrawdownAndLoss(days[:len(c.returns)], c.returns, c.today)
+
+		if drawdown != c.drawdown || dailyLoss != c.dailyLoss {
+			t.Errorf("Bad values for %v on %v: Expected %v, %v and got %v, %v", c.returns, c.today, c.drawdown, c.dailyLoss, drawdown, dailyLoss)
+		}
+	}
+}
+
+//=============================================================================
+
+func TestAddBreach(t *testing.T) {
+	st := &Status{}
+
+	addBreach(st, LimitMaxDrawdown,  100, 0)
+	addBreach(st, LimitMaxDailyLoss, 100, 100)
+	addBreach(st, LimitMaxMargin,    101, 100)
+
+	if breachedLimits(st) != LimitMaxMargin {
+		t.Errorf("Bad breaches: Expected %v and got %v", LimitMaxMargin, breachedLimits(st))
+	}
+}
+
+//=============================================================================
+
+func TestMergeLimits(t *testing.T) {
+	cases := []struct {
+		latched  string
+		current  string
+		expected string
+	}{
+		{ "",                 "",                 "" },
+		{ "maxMargin",        "",                 "maxMargin" },
+		{ "",                 "maxDrawdown",      "maxDrawdown" },
+		{ "maxMargin",        "maxDrawdown",      "maxDrawdown,maxMargin" },
+		{ "maxActiveSystems", "maxActiveSystems", "maxActiveSystems" },
+	}
+
+	for _, c := range cases {
+		if limits := mergeLimits(c.latched, c.current); limits != c.expected {
+			t.Errorf("Bad merge of '%v' and '%v': Expected '%v' and got '%v'", c.latched, c.current, c.expected, limits)
+		}
+	}
+}
+
+//=============================================================================
+
+func TestSelectTargets(t *testing.T) {
+	tsMap := map[uint]*db.TradingSystem{
+		1: { Id: 1, Running: true, Active: true,  MarginValue: 1000 },
+		2: { Id: 2, Running: true, Active: true,  MarginValue: 3000 },
+		3: { Id: 3, Running: true, Active: true,  MarginValue: 2000 },
+		4: { Id: 4, Running: true, Active: true,  MarginValue: 2000 },
+		5: { Id: 5, Running: true, Active: false, MarginValue: 9000 },
+	}
+
+	pc := core.NewProfitCalculator(tsMap)
+	st := &Status{ ActiveSystems: 4, Margin: 8000 }
+
+	cases := []struct {
+		rl       *db.PortfolioRiskLimit
+		limits   string
+		expected []uint
+	}{
+		{ &db.PortfolioRiskLimit{ MaxActiveSystems: 3 },                          "maxActiveSystems",          []uint{ 2 } },
+		{ &db.PortfolioRiskLimit{ MaxActiveSystems: 2 },                          "maxActiveSystems",          []uint{ 2, 3 } },
+		{ &db.PortfolioRiskLimit{ MaxActiveSystems: 4 },                          "maxActiveSystems",          []uint{} },
+		{ &db.PortfolioRiskLimit{ MaxMargin: 4500 },                              "maxMargin",                 []uint{ 2, 3 } },
+		{ &db.PortfolioRiskLimit{ MaxMargin: 4500, TsIds: []uint{ 1, 4 } },       "maxMargin",                 []uint{ 4, 1 } },
+		{ &db.PortfolioRiskLimit{ MaxActiveSystems: 3, MaxDrawdown: 100 },        "maxDrawdown",               []uint{ 2, 3, 4, 1 } },
+		{ &db.PortfolioRiskLimit{ MaxActiveSystems: 3, MaxDailyLoss: 100 },       "maxDailyLoss,maxMargin",    []uint{ 2, 3, 4, 1 } },
+	}
+
+	for _, c := range cases {
+		targets := selectTargets(c.rl, st, c.limits, tsMap, pc)
+
+		ids := []uint{}
+		for _, ts := range targets {
+			ids = append(ids, ts.Id)
+		}
+
+		if len(ids) != len(c.expected) {
+			t.Errorf("Bad targets for %v: Expected %v and got %v", c.limits, c.expected, ids)
+			continue
+		}
+
+		for i := range ids {
+			if ids[i] != c.expected[i] {
+				t.Errorf("Bad targets for %v: Expected %v and got %v", c.limits, c.expected, ids)
+				break
+			}
+		}
+	}
+}
+
+//=============================================================================
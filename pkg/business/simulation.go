@@ -27,6 +27,7 @@ package business
 import (
 	"github.com/tradalia/core/auth"
 	"github.com/tradalia/core/req"
+	"github.com/tradalia/portfolio-trader/pkg/business/filter"
 	"github.com/tradalia/portfolio-trader/pkg/business/simulation"
 	"github.com/tradalia/portfolio-trader/pkg/core"
 	"github.com/tradalia/portfolio-trader/pkg/db"
@@ -36,6 +37,11 @@ import (
 //=============================================================================
 
 func StartSimulation(tx *gorm.DB, c *auth.Context, tsId uint, rq *simulation.Request) error {
+	err := rq.Validate()
+	if err != nil {
+		return err
+	}
+
 	//--- Get trading system
 
 	ts, err := getTradingSystemAndCheckAccess(tx, c, tsId)
@@ -61,7 +67,41 @@ func StartSimulation(tx *gorm.DB, c *auth.Context, tsId uint, rq *simulation.Req
 		return err
 	}
 
-	simulation.Start(rq, ts, trades, risk)
+	returns, err := db.FindDailyReturnsByTsIdFromTime(tx, ts.Id, fromTime, nil)
+	if err != nil {
+		return err
+	}
+
+	if rq.Resampling == "" {
+		rq.Resampling = simulation.ResamplingIid
+	}
+
+	if rq.ChartFormat == "" {
+		rq.ChartFormat = simulation.ChartFormatPng
+	}
+
+	var filtered *[]db.Trade
+
+	if rq.ApplyFilter {
+		var filters *db.TradingFilter
+
+		if rq.Filter == nil {
+			filters, err = db.FindTradingFilterByTsId(tx, tsId)
+			if err != nil {
+				return err
+			}
+
+			if filters == nil {
+				return req.NewUnprocessableEntityError("No stored filter for trading system %v: pass one in the request", tsId)
+			}
+		} else {
+			filters = convert(rq.Filter)
+		}
+
+		filtered = filter.FilterTrades(ts, filters, trades)
+	}
+
+	simulation.Start(rq, ts, trades, returns, filtered, risk)
 	c.Log.Info("StartSimulation: Ending", "id", tsId, "name", ts.Name, "runs", rq.Runs)
 	return nil
 }
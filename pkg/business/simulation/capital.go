@@ -0,0 +1,143 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+
+package simulation
+
+import (
+	"github.com/tradalia/portfolio-trader/pkg/core"
+	"github.com/tradalia/portfolio-trader/pkg/core/stats"
+)
+
+//=============================================================================
+
+var profitableCheckpoints = []int{ 25, 50, 100, 250, 500, 1000 }
+
+//=============================================================================
+//===
+//=== Capital outcomes
+//===
+//=============================================================================
+//--- Converts the R-multiple equities into currency using the risk per trade.
+//--- A run is ruined when its equity falls below the initial capital reduced
+//--- by the ruin percentage
+
+func calcCapitalStats(equities [][]float64, maxDrawdowns []float64, req *Request, risk float64) *CapitalStats {
+	runs := len(equities)
+	if runs == 0 || len(equities[0]) == 0 {
+		return nil
+	}
+
+	capital   := req.InitialCapital
+	ruinLevel := -capital * float64(req.RuinPercentage) / 100
+	size      := len(equities[0])
+
+	var finalEquities []float64
+	var returns       []float64
+	var retOverDD     []float64
+
+	ruined      := 0
+	checkpoints := buildCheckpoints(size)
+	profitable  := make([]int, len(checkpoints))
+
+	for i, equity := range equities {
+		minValue := 0.0
+
+		for _, value := range equity {
+			if value * risk < minValue {
+				minValue = value * risk
+			}
+		}
+
+		if minValue < ruinLevel {
+			ruined++
+		}
+
+		final := equity[size -1] * risk
+		finalEquities = append(finalEquities, capital + final)
+		returns       = append(returns,       final / capital * 100)
+
+		if maxDrawdowns[i] < 0 {
+			retOverDD = append(retOverDD, equity[size -1] / -maxDrawdowns[i])
+		}
+
+		for j, n := range checkpoints {
+			if equity[n -1] > 0 {
+				profitable[j]++
+			}
+		}
+	}
+
+	cs := &CapitalStats{
+		RuinProbability: core.Trunc2d(float64(ruined) * 100 / float64(runs)),
+		FinalEquity    : newPercentiles(finalEquities),
+		Return         : newPercentiles(returns),
+		ReturnDrawdown : newPercentiles(retOverDD),
+		Profitable     : []*ProfitableCheckpoint{},
+	}
+
+	cs.MedianReturn = cs.Return.P50
+
+	for j, n := range checkpoints {
+		cs.Profitable = append(cs.Profitable, &ProfitableCheckpoint{
+			Trades     : n,
+			Probability: core.Trunc2d(float64(profitable[j]) * 100 / float64(runs)),
+		})
+	}
+
+	return cs
+}
+
+//=============================================================================
+
+func buildCheckpoints(size int) []int {
+	var list []int
+
+	for _, n := range profitableCheckpoints {
+		if n < size {
+			list = append(list, n)
+		}
+	}
+
+	return append(list, size)
+}
+
+//=============================================================================
+
+func newPercentiles(data []float64) *Percentiles {
+	if len(data) == 0 {
+		return &Percentiles{}
+	}
+
+	p := stats.NewPercentile(data)
+
+	return &Percentiles{
+		P5 : core.Trunc2d(p.Get( 5)),
+		P25: core.Trunc2d(p.Get(25)),
+		P50: core.Trunc2d(p.Get(50)),
+		P75: core.Trunc2d(p.Get(75)),
+		P95: core.Trunc2d(p.Get(95)),
+	}
+}
+
+//=============================================================================
@@ -29,7 +29,10 @@ import (
 	"log/slog"
 	"math"
 	"math/rand"
+	"runtime"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-analyze/charts"
@@ -46,12 +49,15 @@ import (
 //=============================================================================
 
 type Process struct {
-	ts       *db.TradingSystem
-	trades   *[]db.Trade
-	req      *Request
-	risk     float64
-	result   *Result
-	stopping bool
+	ts        *db.TradingSystem
+	trades    *[]db.Trade
+	returns   *[]db.DailyReturn
+	filtered  *[]db.Trade
+	req       *Request
+	risk      float64
+	result    *Result
+	stopping  atomic.Bool
+	completed int64
 }
 
 //=============================================================================
@@ -61,14 +67,18 @@ const SimStatusWaiting  = "waiting"
 const SimStatusRunning  = "running"
 const SimStatusComplete = "complete"
 
+const ChunkSize = 500
+
 //=============================================================================
 
-func NewProcess(ts *db.TradingSystem, trades *[]db.Trade, req *Request, risk float64) *Process {
+func NewProcess(ts *db.TradingSystem, trades *[]db.Trade, returns *[]db.DailyReturn, filtered *[]db.Trade, req *Request, risk float64) *Process {
 	return &Process{
-		ts    : ts,
-		trades: trades,
-		req   : req,
-		risk  : risk,
+		ts      : ts,
+		trades  : trades,
+		returns : returns,
+		filtered: filtered,
+		req     : req,
+		risk    : risk,
 		result: &Result{
 			Status: SimStatusWaiting,
 		},
@@ -81,8 +91,14 @@ func (p *Process) Start() {
 	slog.Info("SimulationProcess: Starting","id", p.ts.Id)
 
 	p.result = NewResult(p.GetFirstTradeDate(), p.GetLastTradeDate(), p.req.Runs, p.req.InitialCapital, p.req.RuinPercentage, p.risk)
-	p.result.Status    = SimStatusRunning
-	p.result.StartTime = time.Now()
+	p.result.Status     = SimStatusRunning
+	p.result.StartTime  = time.Now()
+	p.result.Resampling = p.req.Resampling
+	p.result.Seed       = p.req.Seed
+
+	if p.result.Seed == 0 {
+		p.result.Seed = time.Now().UnixNano()
+	}
 
 	rMultGrossAll   := core.CalcRMultiple(p.trades, db.TradeTypeAll,   p.risk, 0)
 	rMultGrossLong  := core.CalcRMultiple(p.trades, db.TradeTypeLong,  p.risk, 0)
@@ -91,22 +107,59 @@ func (p *Process) Start() {
 	rMultNetLong    := core.CalcRMultiple(p.trades, db.TradeTypeLong,  p.risk, p.ts.CostPerOperation)
 	rMultNetShort   := core.CalcRMultiple(p.trades, db.TradeTypeShort, p.risk, p.ts.CostPerOperation)
 
-	p.result.GrossAll = run(rMultGrossAll, p.req)
+	//--- Daily returns have no long/short information
+
+	if p.req.Resampling == ResamplingDaily {
+		rMultGrossAll   = p.dailyRMultiple(0)
+		rMultNetAll     = p.dailyRMultiple(p.ts.CostPerOperation)
+		rMultGrossLong  = nil
+		rMultGrossShort = nil
+		rMultNetLong    = nil
+		rMultNetShort   = nil
+	}
+
+	if p.req.Resampling == ResamplingBlock || p.req.Resampling == ResamplingStationary {
+		p.result.BlockLength = calcBlockLength(p.req.BlockLength, len(rMultNetAll))
+	}
+
+	//--- Each series gets its own seed, so results don't depend on the other series
+
+	master := rand.New(rand.NewSource(p.result.Seed))
+	seeds  := make([]int64, 6)
+	for i := range seeds {
+		seeds[i] = master.Int63()
+	}
+
+	for _, list := range [][]float64{ rMultGrossAll, rMultGrossLong, rMultGrossShort, rMultNetAll, rMultNetLong, rMultNetShort } {
+		if len(list) > 0 {
+			p.result.TotalRuns += int64(p.req.Runs)
+		}
+	}
+
+	if p.req.Stress != nil {
+		p.result.TotalRuns += int64(p.req.Runs * countScenarios(p.req.Stress))
+	}
+
+	if p.filtered != nil && len(*p.filtered) > 0 {
+		p.result.TotalRuns += int64(p.req.Runs)
+	}
+
+	p.result.GrossAll = p.run(rMultGrossAll, false, true, seeds[0], 0)
 	p.result.Step++
-	if !p.stopping {
-		p.result.GrossLong = run(rMultGrossLong, p.req)
+	if !p.stopping.Load() {
+		p.result.GrossLong = p.run(rMultGrossLong, false, true, seeds[1], 0)
 		p.result.Step++
-		if !p.stopping {
-			p.result.GrossShort = run(rMultGrossShort, p.req)
+		if !p.stopping.Load() {
+			p.result.GrossShort = p.run(rMultGrossShort, false, true, seeds[2], 0)
 			p.result.Step++
-			if !p.stopping {
-				p.result.NetAll = run(rMultNetAll, p.req)
+			if !p.stopping.Load() {
+				p.result.NetAll = p.run(rMultNetAll, true, true, seeds[3], 0)
 				p.result.Step++
-				if !p.stopping {
-					p.result.NetLong = run(rMultNetLong, p.req)
+				if !p.stopping.Load() {
+					p.result.NetLong = p.run(rMultNetLong, false, true, seeds[4], 0)
 					p.result.Step++
-					if !p.stopping {
-						p.result.NetShort = run(rMultNetShort, p.req)
+					if !p.stopping.Load() {
+						p.result.NetShort = p.run(rMultNetShort, false, true, seeds[5], 0)
 						p.result.Step++
 					}
 				}
@@ -114,6 +167,20 @@ func (p *Process) Start() {
 		}
 	}
 
+	if !p.stopping.Load() && p.req.Stress != nil {
+		p.result.Scenarios = p.runScenarios(master)
+	}
+
+	if !p.stopping.Load() && p.filtered != nil {
+		rMultFiltered := core.CalcRMultiple(p.filtered, db.TradeTypeAll, p.risk, p.ts.CostPerOperation)
+
+		p.result.Filtered = &Filtered{
+			Trades          : len(rMultFiltered),
+			UnfilteredTrades: len(rMultNetAll),
+			NetAll          : p.run(rMultFiltered, false, true, master.Int63(), 0),
+		}
+	}
+
 	p.result.Status  = SimStatusComplete
 	p.result.EndTime = time.Now()
 	slog.Info("SimulationProcess: Ended", "id", p.ts.Id)
@@ -122,13 +189,17 @@ func (p *Process) Start() {
 //=============================================================================
 
 func (p *Process) Stop() {
-	p.stopping = true
+	p.stopping.Store(true)
 }
 
 //=============================================================================
+//--- Chunks update the counter concurrently, so it is read atomically
 
 func (p *Process) GetResult() *Result {
-	return p.result
+	res := *p.result
+	res.CompletedRuns = atomic.LoadInt64(&p.completed)
+
+	return &res
 }
 
 //=============================================================================
@@ -145,61 +216,163 @@ func (p *Process) GetLastTradeDate() datatype.IntDate {
 	return datatype.ToIntDate(t[len(*p.trades) -1].ExitDate)
 }
 
+//=============================================================================
+
+func (p *Process) dailyRMultiple(costPerOper float64) []float64 {
+	_, returns := core.BuildDailyReturns(p.returns, costPerOper)
+
+	for i := range returns {
+		returns[i] /= p.risk
+	}
+
+	return returns
+}
+
+//=============================================================================
+//--- Length of the historical period, used to annualize the returns
+
+func (p *Process) calcYears() float64 {
+	first := p.GetFirstTradeDate().ToDateTime(false, time.UTC)
+	last  := p.GetLastTradeDate() .ToDateTime(true,  time.UTC)
+
+	return last.Sub(first).Hours() / 24 / 365.25
+}
+
+//=============================================================================
+//===
+//=== Public functions
+//===
+//=============================================================================
+//--- Resamples the list (with replacement) into sequences of the given size and
+//--- returns the max drawdown depth and duration (in trades) of each sequence
+
+func SampleDrawdowns(list []float64, runs int, size int) ([]float64, []int) {
+	if len(list) == 0 || size == 0 {
+		return []float64{}, []int{}
+	}
+
+	_, maxDrawdowns, maxDurations := buildSampleSet(buildSamples(list, runs, size, ResamplingIid, 0, rand.New(rand.NewSource(time.Now().UnixNano()))))
+
+	return maxDrawdowns, maxDurations
+}
+
 //=============================================================================
 //===
 //=== Private methods
 //===
 //=============================================================================
+//--- If skipPerc is set, that percentage of the resampled trades is skipped
 
-func run(list []float64, req *Request) *Details {
+func (p *Process) run(list []float64, withSizing bool, withChart bool, seed int64, skipPerc float64) *Details {
+	req  := p.req
 	size := len(list)
 	if size == 0 {
 		return &Details{}
 	}
 
-	sampleSet, maxDrawdowns := buildSampleSet(list, req.Runs)
-	sampleSet = addMeanAndStdDev(sampleSet, size)
+	samples := p.buildSamplesInParallel(list, size, seed, skipPerc)
+	if len(samples) == 0 {
+		return &Details{}
+	}
 
-	p, err := buildChart(sampleSet, req.Width, req.Height)
-	if err != nil {
-		panic(err)
+	sampleSet, maxDrawdowns, _ := buildSampleSet(samples)
+
+	details := &Details{
+		ChartFormat : req.ChartFormat,
+		MaxDrawdowns: buildDDDistrib(maxDrawdowns),
+		Bands       : buildBands(sampleSet, size),
+		Paths       : selectPaths(sampleSet, req.SamplePaths),
+		FinalEquity : newPercentiles(buildFinalValues(sampleSet)),
+		MaxDrawdown : newPercentiles(maxDrawdowns),
+		Capital     : calcCapitalStats(sampleSet, maxDrawdowns, req, p.risk),
 	}
 
-	buf, err := p.Bytes()
-	if err != nil {
-		panic(err)
+	if withSizing && req.Sizing != nil {
+		details.Sizing = runSizing(list, samples, req, p.risk, p.calcYears())
 	}
 
-	return &Details{
-		Equities    : base64.StdEncoding.EncodeToString(buf),
-		MaxDrawdowns: buildDDDistrib(maxDrawdowns),
+	if withChart && req.ChartFormat != ChartFormatNone {
+		sampleSet = addMeanAndStdDev(sampleSet, size)
+
+		painter, err := buildChart(sampleSet, req.Width, req.Height, req.ChartFormat)
+		if err != nil {
+			panic(err)
+		}
+
+		buf, err := painter.Bytes()
+		if err != nil {
+			panic(err)
+		}
+
+		details.Equities = base64.StdEncoding.EncodeToString(buf)
 	}
+
+	return details
 }
 
 //=============================================================================
+//--- Runs are split into chunks, each one with its own random generator. Chunk
+//--- seeds are drawn in order from the series' seed, so the result is the same
+//--- regardless of the scheduling of the goroutines
+
+func (p *Process) buildSamplesInParallel(list []float64, size int, seed int64, skipPerc float64) [][]float64 {
+	req      := p.req
+	blockLen := calcBlockLength(req.BlockLength, size)
+	chunks   := (req.Runs + ChunkSize -1) / ChunkSize
+	results  := make([][][]float64, chunks)
+	seeder   := rand.New(rand.NewSource(seed))
+	limiter  := make(chan struct{}, runtime.NumCPU())
+
+	var wg sync.WaitGroup
+
+	for c:=0; c<chunks; c++ {
+		runs := min(ChunkSize, req.Runs - c * ChunkSize)
+		rng  := rand.New(rand.NewSource(seeder.Int63()))
+
+		wg.Add(1)
+		limiter <- struct{}{}
+
+		go func() {
+			defer wg.Done()
+			defer func() { <-limiter }()
+
+			if !p.stopping.Load() {
+				results[c] = buildSamples(list, runs, size, req.Resampling, blockLen, rng)
+				if skipPerc > 0 {
+					skipTrades(results[c], skipPerc, rng)
+				}
+				atomic.AddInt64(&p.completed, int64(runs))
+			}
+		}()
+	}
 
-func buildSampleSet(list []float64, runs int) ([][]float64, []float64) {
-	size := len(list)
+	wg.Wait()
 
-	var sampleSet    [][]float64
-	var maxDrawdowns []float64
+	var samples [][]float64
+	for _, chunk := range results {
+		samples = append(samples, chunk...)
+	}
 
-	for i:=0; i<runs; i++ {
-		var sample = make([]float64, size)
+	return samples
+}
 
-		for j:=0; j<size; j++ {
-			value := list[rand.Intn(size)]
-			sample[j] = value
-		}
+//=============================================================================
 
+func buildSampleSet(samples [][]float64) ([][]float64, []float64, []int) {
+	var sampleSet    [][]float64
+	var maxDrawdowns []float64
+	var maxDurations []int
+
+	for _, sample := range samples {
 		equity := core.BuildEquity(&sample)
 		sampleSet = append(sampleSet, *equity)
 
-		_, maxDD := core.BuildDrawDown(equity)
+		drawDown, maxDD := core.BuildDrawDown(equity)
 		maxDrawdowns = append(maxDrawdowns, maxDD)
+		maxDurations = append(maxDurations, core.CalcMaxDrawDownDuration(drawDown))
 	}
 
-	return sampleSet, maxDrawdowns
+	return sampleSet, maxDrawdowns, maxDurations
 }
 
 //=============================================================================
@@ -280,7 +453,7 @@ func buildDDDistrib(data []float64) *Distribution {
 //=== Chart building
 //=============================================================================
 
-func buildChart(sampleSet [][]float64, width, height int) (*charts.Painter, error) {
+func buildChart(sampleSet [][]float64, width, height int, format string) (*charts.Painter, error) {
 	xAxis := calcXAxis(len(sampleSet[0]))
 
 	opt := charts.NewLineChartOptionWithData(sampleSet)
@@ -292,8 +465,13 @@ func buildChart(sampleSet [][]float64, width, height int) (*charts.Painter, erro
 	opt.LineStrokeWidth = 1
 	opt.Theme = opt.Theme.WithSeriesColors(buildColors(len(sampleSet)))
 
+	outFormat := charts.ChartOutputPNG
+	if format == ChartFormatSvg {
+		outFormat = charts.ChartOutputSVG
+	}
+
 	p := charts.NewPainter(charts.PainterOptions{
-		OutputFormat: charts.ChartOutputPNG,
+		OutputFormat: outFormat,
 		Width       : width,
 		Height      : height,
 	})
@@ -0,0 +1,141 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+
+package simulation
+
+import (
+	"math"
+	"math/rand"
+)
+
+//=============================================================================
+
+const (
+	ResamplingIid         = "iid"
+	ResamplingBlock       = "block"
+	ResamplingStationary  = "stationary"
+	ResamplingPermutation = "permutation"
+	ResamplingDaily       = "daily"
+)
+
+//=============================================================================
+//===
+//=== Resampling methods
+//===
+//=============================================================================
+
+func buildSamples(list []float64, runs int, size int, method string, blockLen int, rng *rand.Rand) [][]float64 {
+	var samples [][]float64
+
+	for i:=0; i<runs; i++ {
+		var sample []float64
+
+		switch method {
+			case ResamplingBlock:
+				sample = sampleBlocks(list, size, blockLen, false, rng)
+			case ResamplingStationary:
+				sample = sampleBlocks(list, size, blockLen, true, rng)
+			case ResamplingPermutation:
+				sample = samplePermutation(list, size, rng)
+			default:
+				sample = sampleIid(list, size, rng)
+		}
+
+		samples = append(samples, sample)
+	}
+
+	return samples
+}
+
+//=============================================================================
+//--- The default block length grows with the cubic root of the list size
+
+func calcBlockLength(blockLen int, size int) int {
+	if blockLen > 0 {
+		return blockLen
+	}
+
+	return int(math.Max(2, math.Ceil(math.Cbrt(float64(size)))))
+}
+
+//=============================================================================
+//===
+//=== Private functions
+//===
+//=============================================================================
+
+func sampleIid(list []float64, size int, rng *rand.Rand) []float64 {
+	listSize := len(list)
+	sample   := make([]float64, size)
+
+	for j:=0; j<size; j++ {
+		sample[j] = list[rng.Intn(listSize)]
+	}
+
+	return sample
+}
+
+//=============================================================================
+//--- Moving block bootstrap: blocks of consecutive elements are drawn from random
+//--- positions, wrapping around the end of the list. In the stationary version
+//--- (Politis & Romano) the block lengths are geometric with mean blockLen
+
+func sampleBlocks(list []float64, size int, blockLen int, stationary bool, rng *rand.Rand) []float64 {
+	listSize := len(list)
+	sample   := make([]float64, 0, size)
+	prob     := 1 / float64(blockLen)
+
+	for len(sample) < size {
+		start  := rng.Intn(listSize)
+		length := blockLen
+
+		if stationary {
+			length = 1
+			for rng.Float64() > prob {
+				length++
+			}
+		}
+
+		for k:=0; k<length && len(sample) < size; k++ {
+			sample = append(sample, list[(start + k) % listSize])
+		}
+	}
+
+	return sample
+}
+
+//=============================================================================
+//--- Shuffle without replacement: only the order of the elements changes
+
+func samplePermutation(list []float64, size int, rng *rand.Rand) []float64 {
+	sample := make([]float64, size)
+
+	for j, idx := range rng.Perm(len(list))[:size] {
+		sample[j] = list[idx]
+	}
+
+	return sample
+}
+
+//=============================================================================
@@ -24,15 +24,70 @@ THE SOFTWARE.
 
 package simulation
 
+import (
+	"github.com/tradalia/core/req"
+	"github.com/tradalia/portfolio-trader/pkg/business/filter"
+)
+
 //=============================================================================
 
+//--- If ApplyFilter is set and Filter is nil, the stored filter is used. If
+//--- SamplePaths is omitted, DefaultSamplePaths paths are returned. Stress
+//--- scenarios and filters work on trades, so they cannot be used with daily
+//--- resampling
+
 type Request struct {
-	DaysBack        int      `json:"daysBack"       binding:"max=20000"`
-	Runs            int      `json:"runs"           binding:"max=50000"`
-	Width           int      `json:"width"          binding:"max=4000"`
-	Height          int      `json:"height"         binding:"max=3000"`
-	InitialCapital  float64  `json:"initialCapital" binding:"min=1"`
-	RuinPercentage  int      `json:"ruinPercentage" binding:"min=5,max=95"`
+	DaysBack        int                   `json:"daysBack"       binding:"max=20000"`
+	Runs            int                   `json:"runs"           binding:"max=50000"`
+	Width           int                   `json:"width"          binding:"max=4000"`
+	Height          int                   `json:"height"         binding:"max=3000"`
+	InitialCapital  float64               `json:"initialCapital" binding:"min=1"`
+	RuinPercentage  int                   `json:"ruinPercentage" binding:"min=5,max=95"`
+	Resampling      string                `json:"resampling"     binding:"omitempty,oneof=iid block stationary permutation daily"`
+	BlockLength     int                   `json:"blockLength"    binding:"min=0,max=250"`
+	ChartFormat     string                `json:"chartFormat"    binding:"omitempty,oneof=png svg none"`
+	SamplePaths     *int                  `json:"samplePaths"    binding:"omitempty,min=0,max=100"`
+	Seed            int64                 `json:"seed"`
+	Stress          *Stress               `json:"stress"`
+	Sizing          *Sizing               `json:"sizing"`
+	ApplyFilter     bool                  `json:"applyFilter"`
+	Filter          *filter.TradingFilter `json:"filter"`
+}
+
+//=============================================================================
+
+func (r *Request) Validate() error {
+	if r.Stress != nil && r.Resampling == ResamplingDaily {
+		return req.NewBadRequestError("Stress scenarios cannot be used with daily resampling")
+	}
+
+	if r.ApplyFilter && r.Resampling == ResamplingDaily {
+		return req.NewBadRequestError("Filters cannot be applied with daily resampling")
+	}
+
+	return nil
+}
+
+//=============================================================================
+//--- Parameters of the position sizing methods. Zero values get defaults
+
+type Sizing struct {
+	RiskPercentage   float64 `json:"riskPercentage"   binding:"min=0,max=100"`
+	FixedRatioDelta  float64 `json:"fixedRatioDelta"  binding:"min=0"`
+	KellyFraction    float64 `json:"kellyFraction"    binding:"min=0,max=1"`
+	EquityCurveLen   int     `json:"equityCurveLen"   binding:"min=0,max=500"`
+	EquityCurveScale float64 `json:"equityCurveScale" binding:"min=0,max=1"`
+}
+
+//=============================================================================
+//--- Stress scenarios. Zero values disable the related stress
+
+type Stress struct {
+	CostMultiplier       float64 `json:"costMultiplier"       binding:"min=0,max=100"`
+	SlippageTicks        int     `json:"slippageTicks"        binding:"min=0,max=100"`
+	RemoveTopWinnersPerc float64 `json:"removeTopWinnersPerc" binding:"min=0,max=100"`
+	SkipTradesPerc       float64 `json:"skipTradesPerc"       binding:"min=0,max=99"`
+	LoserMultiplier      float64 `json:"loserMultiplier"      binding:"min=0,max=100"`
 }
 
 //=============================================================================
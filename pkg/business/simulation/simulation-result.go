@@ -31,6 +31,8 @@ import (
 )
 
 //=============================================================================
+//--- Step (the number of completed series) is deprecated: use CompletedRuns
+//--- and TotalRuns to track the progress
 
 type Result struct {
 	FirstTradeDate datatype.IntDate  `json:"firstTradeDate"`
@@ -39,11 +41,16 @@ type Result struct {
 	InitialCapital float64           `json:"initialCapital"`
 	RuinPercentage int               `json:"ruinPercentage"`
 	Risk           float64           `json:"risk"`
+	Resampling     string            `json:"resampling"`
+	BlockLength    int               `json:"blockLength"`
 
 	Status         string            `json:"status"`
 	StartTime      time.Time         `json:"startTime"`
 	EndTime        time.Time         `json:"endTime"`
 	Step           int               `json:"step"`
+	Seed           int64             `json:"seed"`
+	TotalRuns      int64             `json:"totalRuns"`
+	CompletedRuns  int64             `json:"completedRuns"`
 
 	GrossAll       *Details          `json:"grossAll"`
 	GrossLong      *Details          `json:"grossLong"`
@@ -51,6 +58,8 @@ type Result struct {
 	NetAll         *Details          `json:"netAll"`
 	NetLong        *Details          `json:"netLong"`
 	NetShort       *Details          `json:"netShort"`
+	Scenarios      []*Scenario       `json:"scenarios"`
+	Filtered       *Filtered         `json:"filtered"`
 }
 
 //=============================================================================
@@ -68,9 +77,30 @@ func NewResult(first, last datatype.IntDate, runs int, initialCapital float64, r
 
 //=============================================================================
 
+//--- Equities is the chart (base64) in the requested format. Bands, paths and
+//--- percentiles are expressed in cumulative R multiples
+
 type Details struct {
-	Equities     string        `json:"equities"`
-	MaxDrawdowns *Distribution `json:"maxDrawdowns"`
+	ChartFormat  string          `json:"chartFormat"`
+	Equities     string          `json:"equities"`
+	MaxDrawdowns *Distribution   `json:"maxDrawdowns"`
+	Bands        *Bands          `json:"bands"`
+	Paths        [][]float64     `json:"paths"`
+	FinalEquity  *Percentiles    `json:"finalEquity"`
+	MaxDrawdown  *Percentiles    `json:"maxDrawdown"`
+	Capital      *CapitalStats   `json:"capital"`
+	Sizing       []*SizingResult `json:"sizing"`
+}
+
+//=============================================================================
+//--- Percentiles of the cumulative R multiple at each trade
+
+type Bands struct {
+	P5  []float64 `json:"p5"`
+	P25 []float64 `json:"p25"`
+	P50 []float64 `json:"p50"`
+	P75 []float64 `json:"p75"`
+	P95 []float64 `json:"p95"`
 }
 
 //=============================================================================
@@ -81,3 +111,60 @@ type Distribution struct {
 }
 
 //=============================================================================
+
+type CapitalStats struct {
+	RuinProbability float64                 `json:"ruinProbability"`
+	MedianReturn    float64                 `json:"medianReturn"`
+	FinalEquity     *Percentiles            `json:"finalEquity"`
+	Return          *Percentiles            `json:"return"`
+	ReturnDrawdown  *Percentiles            `json:"returnDrawdown"`
+	Profitable      []*ProfitableCheckpoint `json:"profitable"`
+}
+
+//=============================================================================
+
+type Percentiles struct {
+	P5  float64 `json:"p5"`
+	P25 float64 `json:"p25"`
+	P50 float64 `json:"p50"`
+	P75 float64 `json:"p75"`
+	P95 float64 `json:"p95"`
+}
+
+//=============================================================================
+//--- Probability (in %) of being profitable after the given number of trades
+
+type ProfitableCheckpoint struct {
+	Trades      int     `json:"trades"`
+	Probability float64 `json:"probability"`
+}
+
+//=============================================================================
+
+type SizingResult struct {
+	Method          string       `json:"method"`
+	RuinProbability float64      `json:"ruinProbability"`
+	FinalEquity     *Percentiles `json:"finalEquity"`
+	Cagr            *Percentiles `json:"cagr"`
+	MaxDrawdownPerc *Percentiles `json:"maxDrawdownPerc"`
+}
+
+//=============================================================================
+//--- Stress scenario, to be compared with the NetAll baseline
+
+type Scenario struct {
+	Name    string   `json:"name"`
+	Trades  int      `json:"trades"`
+	Details *Details `json:"details"`
+}
+
+//=============================================================================
+//--- Simulation of the trades taken with the filter, to be compared with NetAll
+
+type Filtered struct {
+	Trades           int      `json:"trades"`
+	UnfilteredTrades int      `json:"unfilteredTrades"`
+	NetAll           *Details `json:"netAll"`
+}
+
+//=============================================================================
@@ -0,0 +1,241 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+
+package simulation
+
+import (
+	"math"
+
+	"github.com/tradalia/portfolio-trader/pkg/core"
+	"github.com/tradalia/portfolio-trader/pkg/core/stats"
+)
+
+//=============================================================================
+
+const (
+	SizingFixed           = "fixed"
+	SizingFixedFractional = "fixedFractional"
+	SizingFixedRatio      = "fixedRatio"
+	SizingFractionalKelly = "fractionalKelly"
+	SizingEquityCurve     = "equityCurve"
+)
+
+const (
+	DefaultRiskPercentage   = 2.0
+	DefaultKellyFraction    = 0.5
+	DefaultEquityCurveLen   = 20
+	DefaultEquityCurveScale = 0.5
+)
+
+//=============================================================================
+//--- Returns the number of contracts to trade given the current equity and
+//--- the equity curve so far (first element is the initial capital)
+
+type contractsFunc func(equity float64, curve []float64) float64
+
+//=============================================================================
+//===
+//=== Position sizing
+//===
+//=============================================================================
+//--- Applies each sizing method to the same resampled sequences of R-multiples.
+//--- One R is the risk of a single contract. A run stops when ruined
+
+func runSizing(list []float64, samples [][]float64, req *Request, risk float64, years float64) []*SizingResult {
+	cfg := applySizingDefaults(req.Sizing, list, risk)
+
+	capital   := req.InitialCapital
+	ruinLevel := capital * (1 - float64(req.RuinPercentage) / 100)
+	fraction  := cfg.RiskPercentage / 100
+
+	fixedFractional := func(equity float64, curve []float64) float64 {
+		return math.Floor(equity * fraction / risk)
+	}
+
+	//--- Optimal f is referred to the biggest loss, as for R. Vince
+
+	biggestLoss := math.Abs(core.CalcMin(list))
+	optimalF    := calcOptimalF(list, biggestLoss)
+
+	methods := []struct {
+		name      string
+		contracts contractsFunc
+	}{
+		{ SizingFixed, func(equity float64, curve []float64) float64 {
+			return 1
+		}},
+		{ SizingFixedFractional, fixedFractional },
+		{ SizingFixedRatio, func(equity float64, curve []float64) float64 {
+			profit := equity - capital
+			if profit <= 0 {
+				return 1
+			}
+
+			return math.Floor(0.5 * (1 + math.Sqrt(1 + 8 * profit / cfg.FixedRatioDelta)))
+		}},
+		{ SizingFractionalKelly, func(equity float64, curve []float64) float64 {
+			if biggestLoss == 0 {
+				return 1
+			}
+
+			return math.Floor(equity * optimalF * cfg.KellyFraction / (biggestLoss * risk))
+		}},
+		{ SizingEquityCurve, func(equity float64, curve []float64) float64 {
+			contracts := fixedFractional(equity, curve)
+			size      := cfg.EquityCurveLen
+
+			if len(curve) >= size && equity < stats.Mean(curve[len(curve) - size:]) {
+				contracts = math.Floor(contracts * cfg.EquityCurveScale)
+			}
+
+			return contracts
+		}},
+	}
+
+	var results []*SizingResult
+
+	for _, m := range methods {
+		results = append(results, runSizingMethod(m.name, m.contracts, samples, capital, ruinLevel, risk, years))
+	}
+
+	return results
+}
+
+//=============================================================================
+
+func applySizingDefaults(s *Sizing, list []float64, risk float64) Sizing {
+	cfg := *s
+
+	if cfg.RiskPercentage == 0 {
+		cfg.RiskPercentage = DefaultRiskPercentage
+	}
+
+	if cfg.KellyFraction == 0 {
+		cfg.KellyFraction = DefaultKellyFraction
+	}
+
+	if cfg.EquityCurveLen == 0 {
+		cfg.EquityCurveLen = DefaultEquityCurveLen
+	}
+
+	if cfg.EquityCurveScale == 0 {
+		cfg.EquityCurveScale = DefaultEquityCurveScale
+	}
+
+	//--- As suggested by R. Jones, delta is half the historical max drawdown (of 1 contract)
+
+	if cfg.FixedRatioDelta == 0 {
+		_, maxDD := core.BuildDrawDown(core.BuildEquity(&list))
+		cfg.FixedRatioDelta = math.Abs(maxDD) * risk / 2
+	}
+
+	if cfg.FixedRatioDelta == 0 {
+		cfg.FixedRatioDelta = risk
+	}
+
+	return cfg
+}
+
+//=============================================================================
+
+func runSizingMethod(name string, contracts contractsFunc, samples [][]float64, capital, ruinLevel, risk, years float64) *SizingResult {
+	var finalEquities []float64
+	var cagrs         []float64
+	var maxDDs        []float64
+
+	ruined := 0
+
+	for _, sample := range samples {
+		equity := capital
+		peak   := capital
+		maxDD  := 0.0
+		curve  := []float64{ capital }
+
+		for _, r := range sample {
+			equity += r * risk * math.Max(0, contracts(equity, curve))
+			curve   = append(curve, equity)
+
+			if equity > peak {
+				peak = equity
+			} else if dd := (equity - peak) / peak * 100; dd < maxDD {
+				maxDD = dd
+			}
+
+			if equity < ruinLevel {
+				ruined++
+				break
+			}
+		}
+
+		finalEquities = append(finalEquities, equity)
+		cagrs         = append(cagrs,         stats.Cagr(capital, equity, years))
+		maxDDs        = append(maxDDs,        maxDD)
+	}
+
+	return &SizingResult{
+		Method         : name,
+		RuinProbability: core.Trunc2d(float64(ruined) * 100 / float64(len(samples))),
+		FinalEquity    : newPercentiles(finalEquities),
+		Cagr           : newPercentiles(cagrs),
+		MaxDrawdownPerc: newPercentiles(maxDDs),
+	}
+}
+
+//=============================================================================
+//--- Finds the fraction f that maximizes the terminal wealth relative, with
+//--- holding period returns 1 + f * (trade / biggestLoss), where biggestLoss
+//--- is the absolute value of the worst trade
+
+func calcOptimalF(list []float64, biggestLoss float64) float64 {
+	if biggestLoss == 0 {
+		return 1
+	}
+
+	bestF   := 0.0
+	bestTwr := 0.0
+
+	for f := 0.01; f <= 1; f += 0.01 {
+		twr := 0.0
+		ok  := true
+
+		for _, trade := range list {
+			hpr := 1 + f * trade / biggestLoss
+			if hpr <= 0 {
+				ok = false
+				break
+			}
+
+			twr += math.Log(hpr)
+		}
+
+		if ok && twr > bestTwr {
+			bestTwr = twr
+			bestF   = f
+		}
+	}
+
+	return bestF
+}
+
+//=============================================================================
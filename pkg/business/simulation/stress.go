@@ -0,0 +1,290 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+
+package simulation
+
+import (
+	"cmp"
+	"math"
+	"math/rand"
+	"slices"
+	"sync/atomic"
+
+	"github.com/tradalia/portfolio-trader/pkg/db"
+)
+
+//=============================================================================
+
+const (
+	ScenarioCost          = "cost"
+	ScenarioSlippage      = "slippage"
+	ScenarioTopWinners    = "topWinners"
+	ScenarioSkippedTrades = "skippedTrades"
+	ScenarioLosers        = "losers"
+	ScenarioCombined      = "combined"
+)
+
+//=============================================================================
+//--- A stress modifies the net profits of the trades. Skipped trades are not
+//--- a stress function: they are removed from the resampled paths instead
+
+type stressFunc func(profits []float64) []float64
+
+//=============================================================================
+//===
+//=== Stress scenarios
+//===
+//=============================================================================
+//--- Every enabled stress is run on its own and, if there are more than one,
+//--- all together. Scenarios are based on the net results of all trades, like
+//--- the NetAll series, and use the same resampling
+
+func (p *Process) runScenarios(master *rand.Rand) []*Scenario {
+	stress := p.req.Stress
+	names, funcs := p.buildStressFuncs(stress)
+
+	if len(funcs) > 1 {
+		singles := slices.Clone(funcs)
+		names = append(names, ScenarioCombined)
+		funcs = append(funcs, func(profits []float64) []float64 {
+			for _, f := range singles {
+				profits = f(profits)
+			}
+
+			return profits
+		})
+	}
+
+	var scenarios []*Scenario
+
+	for i, name := range names {
+		if p.stopping.Load() {
+			break
+		}
+
+		seed    := master.Int63()
+		profits := funcs[i](p.buildNetProfits(stress.CostMultiplier, name))
+
+		rMult := make([]float64, len(profits))
+		for j, value := range profits {
+			rMult[j] = value / p.risk
+		}
+
+		skipPerc := 0.0
+		if name == ScenarioSkippedTrades || name == ScenarioCombined {
+			skipPerc = stress.SkipTradesPerc
+		}
+
+		//--- Nothing to resample, but runs are part of the total
+
+		if len(rMult) == 0 {
+			atomic.AddInt64(&p.completed, int64(p.req.Runs))
+		}
+
+		scenarios = append(scenarios, &Scenario{
+			Name   : name,
+			Trades : len(rMult),
+			Details: p.run(rMult, false, false, seed, skipPerc),
+		})
+	}
+
+	return scenarios
+}
+
+//=============================================================================
+
+func countScenarios(stress *Stress) int {
+	count := 0
+
+	if stress.CostMultiplier > 0 && stress.CostMultiplier != 1 {
+		count++
+	}
+
+	for _, value := range []float64{ float64(stress.SlippageTicks), stress.RemoveTopWinnersPerc, stress.SkipTradesPerc } {
+		if value > 0 {
+			count++
+		}
+	}
+
+	if stress.LoserMultiplier > 0 && stress.LoserMultiplier != 1 {
+		count++
+	}
+
+	if count > 1 {
+		count++
+	}
+
+	return count
+}
+
+//=============================================================================
+//===
+//=== Private methods
+//===
+//=============================================================================
+//--- The cost multiplier is applied when building the profits, only for the
+//--- scenarios that include it
+
+func (p *Process) buildNetProfits(costMultiplier float64, name string) []float64 {
+	cost := p.ts.CostPerOperation
+
+	if (name == ScenarioCost || name == ScenarioCombined) && costMultiplier > 0 {
+		cost *= costMultiplier
+	}
+
+	var list []float64
+
+	for _, t := range *p.trades {
+		list = append(list, t.GrossProfit - 2 * cost)
+	}
+
+	return list
+}
+
+//=============================================================================
+
+func (p *Process) buildStressFuncs(stress *Stress) ([]string, []stressFunc) {
+	var names []string
+	var funcs []stressFunc
+
+	if stress.CostMultiplier > 0 && stress.CostMultiplier != 1 {
+		names = append(names, ScenarioCost)
+		funcs = append(funcs, func(profits []float64) []float64 {
+			return profits
+		})
+	}
+
+	if stress.SlippageTicks > 0 {
+		names = append(names, ScenarioSlippage)
+		funcs = append(funcs, func(profits []float64) []float64 {
+			return applySlippage(profits, p.ts, stress.SlippageTicks)
+		})
+	}
+
+	if stress.RemoveTopWinnersPerc > 0 {
+		names = append(names, ScenarioTopWinners)
+		funcs = append(funcs, func(profits []float64) []float64 {
+			return removeTopWinners(profits, stress.RemoveTopWinnersPerc)
+		})
+	}
+
+	if stress.SkipTradesPerc > 0 {
+		names = append(names, ScenarioSkippedTrades)
+		funcs = append(funcs, func(profits []float64) []float64 {
+			return profits
+		})
+	}
+
+	if stress.LoserMultiplier > 0 && stress.LoserMultiplier != 1 {
+		names = append(names, ScenarioLosers)
+		funcs = append(funcs, func(profits []float64) []float64 {
+			return scaleLosers(profits, stress.LoserMultiplier)
+		})
+	}
+
+	return names, funcs
+}
+
+//=============================================================================
+//===
+//=== Private functions
+//===
+//=============================================================================
+//--- Slippage is applied on both entry and exit, like the cost per operation
+
+func applySlippage(profits []float64, ts *db.TradingSystem, ticks int) []float64 {
+	slippage := 2 * float64(ticks) * ts.Increment * ts.PointValue
+	res      := make([]float64, len(profits))
+
+	for i, value := range profits {
+		res[i] = value - slippage
+	}
+
+	return res
+}
+
+//=============================================================================
+
+func removeTopWinners(profits []float64, perc float64) []float64 {
+	var winners []int
+
+	for i, value := range profits {
+		if value > 0 {
+			winners = append(winners, i)
+		}
+	}
+
+	toRemove := int(math.Ceil(float64(len(winners)) * perc / 100))
+
+	slices.SortStableFunc(winners, func(a, b int) int {
+		return cmp.Compare(profits[b], profits[a])
+	})
+
+	removed := map[int]bool{}
+	for _, idx := range winners[:toRemove] {
+		removed[idx] = true
+	}
+
+	var res []float64
+
+	for i, value := range profits {
+		if !removed[i] {
+			res = append(res, value)
+		}
+	}
+
+	return res
+}
+
+//=============================================================================
+//--- Skipped trades are replaced by 0, so that the paths keep the length of the
+//--- historical period (a shorter path would have smaller drawdowns)
+
+func skipTrades(samples [][]float64, perc float64, rng *rand.Rand) {
+	for _, sample := range samples {
+		for i := range sample {
+			if rng.Float64() * 100 < perc {
+				sample[i] = 0
+			}
+		}
+	}
+}
+
+//=============================================================================
+
+func scaleLosers(profits []float64, multiplier float64) []float64 {
+	res := make([]float64, len(profits))
+
+	for i, value := range profits {
+		if value < 0 {
+			value *= multiplier
+		}
+
+		res[i] = value
+	}
+
+	return res
+}
+
+//=============================================================================
@@ -0,0 +1,103 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+
+package simulation
+
+import (
+	"github.com/tradalia/portfolio-trader/pkg/core"
+	"github.com/tradalia/portfolio-trader/pkg/core/stats"
+)
+
+//=============================================================================
+
+const (
+	ChartFormatPng  = "png"
+	ChartFormatSvg  = "svg"
+	ChartFormatNone = "none"
+)
+
+const DefaultSamplePaths = 10
+
+//=============================================================================
+//===
+//=== Structured results
+//===
+//=============================================================================
+
+func buildBands(sampleSet [][]float64, size int) *Bands {
+	b := &Bands{
+		P5 : make([]float64, size),
+		P25: make([]float64, size),
+		P50: make([]float64, size),
+		P75: make([]float64, size),
+		P95: make([]float64, size),
+	}
+
+	serie := make([]float64, len(sampleSet))
+
+	for i:=0; i<size; i++ {
+		for j, equity := range sampleSet {
+			serie[j] = equity[i]
+		}
+
+		p := stats.NewPercentile(serie)
+		b.P5 [i] = core.Trunc2d(p.Get( 5))
+		b.P25[i] = core.Trunc2d(p.Get(25))
+		b.P50[i] = core.Trunc2d(p.Get(50))
+		b.P75[i] = core.Trunc2d(p.Get(75))
+		b.P95[i] = core.Trunc2d(p.Get(95))
+	}
+
+	return b
+}
+
+//=============================================================================
+//--- Runs are already random, so the first ones are a fair sample
+
+func selectPaths(sampleSet [][]float64, paths *int) [][]float64 {
+	count := DefaultSamplePaths
+	if paths != nil {
+		count = *paths
+	}
+
+	if count > len(sampleSet) {
+		count = len(sampleSet)
+	}
+
+	return sampleSet[:count]
+}
+
+//=============================================================================
+
+func buildFinalValues(sampleSet [][]float64) []float64 {
+	var list []float64
+
+	for _, equity := range sampleSet {
+		list = append(list, equity[len(equity) -1])
+	}
+
+	return list
+}
+
+//=============================================================================
@@ -55,6 +55,29 @@ func getTradingSystemAndCheckAccess(tx *gorm.DB, c *auth.Context, id uint) (*db.
 	return ts, nil
 }
 
+//=============================================================================
+//--- FX rates are converted into the base currency of the owner of the
+//--- trading systems, which differs from the session user when an admin runs
+//--- the analysis. With no systems, the session user is the owner
+
+func getTradingSystemsOwner(c *auth.Context, tsMap map[uint]*db.TradingSystem) (string, error) {
+	owner := ""
+
+	for id, ts := range tsMap {
+		if owner == "" {
+			owner = ts.Username
+		} else if ts.Username != owner {
+			return "", req.NewBadRequestError("Trading systems belong to different users: %v", id)
+		}
+	}
+
+	if owner == "" {
+		owner = c.Session.Username
+	}
+
+	return owner, nil
+}
+
 //=============================================================================
 
 func calcBackPeriod(daysBack int) *time.Time {
@@ -25,7 +25,11 @@ THE SOFTWARE.
 package business
 
 import (
+	"time"
+
 	"github.com/tradalia/core/auth"
+	"github.com/tradalia/core/datatype"
+	"github.com/tradalia/core/req"
 	"github.com/tradalia/portfolio-trader/pkg/db"
 	"gorm.io/gorm"
 )
@@ -37,9 +41,13 @@ type TradingSystemTradingRequest struct {
 }
 
 //=============================================================================
+//--- The first time a system starts running, its live period begins: LiveFrom
+//--- sets that day explicitly, otherwise today (in the exchange's timezone) is
+//--- used. Later restarts keep the stored day, unless LiveFrom is provided
 
 type TradingSystemRunningRequest struct {
-	Value bool `json:"value"`
+	Value    bool             `json:"value"`
+	LiveFrom datatype.IntDate `json:"liveFrom"`
 }
 
 //=============================================================================
@@ -135,6 +143,13 @@ func SetTradingSystemRunning(tx *gorm.DB, c *auth.Context, tsId uint, req *Tradi
 		}, nil
 	}
 
+	if newValue {
+		err = updateLiveFrom(ts, req.LiveFrom)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	ts.Running = newValue
 	updateStatus(ts)
 	err = db.UpdateTradingSystem(tx, ts)
@@ -235,7 +250,10 @@ func SetTradingSystemActive(tx *gorm.DB, c *auth.Context, tsId uint, req *Tradin
 //=============================================================================
 
 func updateStatus(ts *db.TradingSystem) {
-	ts.SuggestedAction = db.TsActionNone
+	ts.SuggestedAction  = db.TsActionNone
+	ts.DegradationDate  = nil
+	ts.DegradationStat  = 0
+	ts.DegradationLimit = 0
 
 	if ! ts.Running {
 		ts.Status = db.TsStatusOff
@@ -246,6 +264,30 @@ func updateStatus(ts *db.TradingSystem) {
 	}
 }
 
+//=============================================================================
+
+func updateLiveFrom(ts *db.TradingSystem, liveFrom datatype.IntDate) error {
+	if liveFrom.IsNil() {
+		if !ts.LiveFrom.IsNil() {
+			return nil
+		}
+
+		loc, err := time.LoadLocation(ts.Timezone)
+		if err != nil {
+			loc = time.UTC
+		}
+
+		liveFrom = datatype.Today(loc)
+	}
+
+	if !ts.InSampleTo.IsNil() && liveFrom <= ts.InSampleTo {
+		return req.NewBadRequestError("Live period must start after the development period (%v): %v", ts.InSampleTo, liveFrom)
+	}
+
+	ts.LiveFrom = liveFrom
+	return nil
+}
+
 //============================================================================
 
 func updateRewind(ts *db.TradingSystem) error {
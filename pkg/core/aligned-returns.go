@@ -0,0 +1,145 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+
+package core
+
+import (
+	"sort"
+	"time"
+
+	"github.com/tradalia/core/datatype"
+	"github.com/tradalia/portfolio-trader/pkg/db"
+)
+
+//=============================================================================
+//--- Daily (net) returns of several trading systems on a common timeline.
+//--- First and Last hold, for each system, the indexes of the first and last
+//--- day with a return. Outside that range the system was not trading
+
+type AlignedReturns struct {
+	Days    []datatype.IntDate
+	Returns map[uint][]float64
+	First   map[uint]int
+	Last    map[uint]int
+}
+
+//=============================================================================
+//--- The timeline goes from the first to the last day of all returns. Weekdays
+//--- without trading get a zero return. Weekend days are kept only if some
+//--- system has a return
+
+func BuildAlignedReturns(list *[]db.DailyReturn, pc *ProfitCalculator) *AlignedReturns {
+	ar := &AlignedReturns{
+		Returns: map[uint][]float64{},
+		First  : map[uint]int{},
+		Last   : map[uint]int{},
+	}
+
+	if list == nil || len(*list) == 0 {
+		return ar
+	}
+
+	dayMap := map[datatype.IntDate]bool{}
+	first  := (*list)[0].Day
+	last   := first
+
+	for _, dr := range *list {
+		dayMap[dr.Day] = true
+
+		if dr.Day < first {
+			first = dr.Day
+		}
+
+		if dr.Day > last {
+			last = dr.Day
+		}
+	}
+
+	//--- Build the timeline
+
+	indexMap := map[datatype.IntDate]int{}
+
+	for day := first; day <= last; day = day.AddDays(1) {
+		wd := day.ToDateTime(false, time.UTC).Weekday()
+
+		if dayMap[day] || (wd != time.Saturday && wd != time.Sunday) {
+			indexMap[day] = len(ar.Days)
+			ar.Days = append(ar.Days, day)
+		}
+	}
+
+	//--- Fill the returns of each system
+
+	for i := range *list {
+		dr    := &(*list)[i]
+		id    := dr.TradingSystemId
+		index := indexMap[dr.Day]
+
+		returns, ok := ar.Returns[id]
+		if !ok {
+			returns = make([]float64, len(ar.Days))
+			ar.Returns[id] = returns
+			ar.First  [id] = index
+			ar.Last   [id] = index
+		}
+
+		returns[index] += pc.NetDailyReturn(dr)
+
+		if index < ar.First[id] {
+			ar.First[id] = index
+		}
+
+		if index > ar.Last[id] {
+			ar.Last[id] = index
+		}
+	}
+
+	return ar
+}
+
+//=============================================================================
+
+func (ar *AlignedReturns) TradingSystemIds() []uint {
+	var ids []uint
+
+	for id := range ar.Returns {
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+
+	return ids
+}
+
+//=============================================================================
+//--- Returns the range [from, to] of days where both systems were trading.
+//--- The range is empty (from > to) if the systems never overlapped
+
+func (ar *AlignedReturns) CommonRange(id1, id2 uint) (int, int) {
+	return max(ar.First[id1], ar.First[id2]), min(ar.Last[id1], ar.Last[id2])
+}
+
+//=============================================================================
@@ -103,6 +103,28 @@ func BuildDrawDown(equity *[]float64) (*[]float64, float64) {
 	return &drawDown, maxDrawDown
 }
 
+//=============================================================================
+//--- Returns the longest sequence of consecutive elements under water
+
+func CalcMaxDrawDownDuration(drawDown *[]float64) int {
+	currDuration := 0
+	maxDuration  := 0
+
+	for _, value := range *drawDown {
+		if value < 0 {
+			currDuration++
+
+			if currDuration > maxDuration {
+				maxDuration = currDuration
+			}
+		} else {
+			currDuration = 0
+		}
+	}
+
+	return maxDuration
+}
+
 //=============================================================================
 
 func CalcWinningPercentage(profits []float64, filter []int8) float64 {
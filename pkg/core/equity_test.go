@@ -65,3 +65,19 @@ func TestBuildDrawDown(t *testing.T) {
 }
 
 //=============================================================================
+
+var ddown2 = []float64{ 0, -1, -2, 0, -1 }
+
+//=============================================================================
+
+func TestCalcMaxDrawDownDuration(t *testing.T) {
+	if d := CalcMaxDrawDownDuration(&ddown1); d != 1 {
+		t.Errorf("Bad max drawdown duration. Expected 1 but got %v", d)
+	}
+
+	if d := CalcMaxDrawDownDuration(&ddown2); d != 2 {
+		t.Errorf("Bad max drawdown duration. Expected 2 but got %v", d)
+	}
+}
+
+//=============================================================================
@@ -0,0 +1,269 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+
+
+package core
+
+import (
+	"sort"
+
+	"github.com/tradalia/core/datatype"
+	"github.com/tradalia/core/req"
+	"github.com/tradalia/portfolio-trader/pkg/db"
+	"gorm.io/gorm"
+)
+
+//=============================================================================
+//--- Converts amounts into a base currency using daily rates. A currency is
+//--- converted with its direct or inverse pair against the base currency or,
+//--- if missing, crossing through a third currency. On days without a rate
+//--- the most recent one is used
+
+type FxConverter struct {
+	base   string
+	series map[string]*fxSeries
+	paths  map[string][]*fxStep
+}
+
+//-----------------------------------------------------------------------------
+
+type fxSeries struct {
+	days  []datatype.IntDate
+	rates []float64
+}
+
+//-----------------------------------------------------------------------------
+
+type fxStep struct {
+	series  *fxSeries
+	inverse bool
+}
+
+//=============================================================================
+
+func NewFxConverter(base string, rates *[]db.FxRate) *FxConverter {
+	fx := &FxConverter{
+		base  : base,
+		series: map[string]*fxSeries{},
+		paths : map[string][]*fxStep{},
+	}
+
+	currencies := map[string]bool{}
+
+	if rates != nil {
+		for _, r := range *rates {
+			if r.Rate <= 0 {
+				continue
+			}
+
+			key := pairKey(r.BaseCurrency, r.QuoteCurrency)
+			s, ok := fx.series[key]
+			if !ok {
+				s = &fxSeries{}
+				fx.series[key] = s
+			}
+
+			s.days  = append(s.days,  r.Day)
+			s.rates = append(s.rates, r.Rate)
+
+			currencies[r.BaseCurrency]  = true
+			currencies[r.QuoteCurrency] = true
+		}
+	}
+
+	for _, s := range fx.series {
+		sort.Sort(s)
+	}
+
+	for currency := range currencies {
+		if path := fx.findPath(currency, currencies); path != nil {
+			fx.paths[currency] = path
+		}
+	}
+
+	return fx
+}
+
+//=============================================================================
+
+func (fx *FxConverter) BaseCurrency() string {
+	return fx.base
+}
+
+//=============================================================================
+//--- An empty currency is considered to be the base one
+
+func (fx *FxConverter) CanConvert(currency string) bool {
+	if currency == "" || currency == fx.base {
+		return true
+	}
+
+	_, ok := fx.paths[currency]
+	return ok
+}
+
+//=============================================================================
+//--- Returns 1 if the currency cannot be converted
+
+func (fx *FxConverter) Rate(currency string, day datatype.IntDate) float64 {
+	if currency == "" || currency == fx.base {
+		return 1
+	}
+
+	rate := 1.0
+
+	for _, step := range fx.paths[currency] {
+		if step.inverse {
+			rate /= step.series.rateAt(day)
+		} else {
+			rate *= step.series.rateAt(day)
+		}
+	}
+
+	return rate
+}
+
+//=============================================================================
+
+func (fx *FxConverter) Convert(amount float64, currency string, day datatype.IntDate) float64 {
+	return amount * fx.Rate(currency, day)
+}
+
+//=============================================================================
+//===
+//=== Factory
+//===
+//=============================================================================
+//--- Builds a calculator that converts into the base currency of the user. If
+//--- the user has no base currency, amounts are left in the currency of each
+//--- trading system
+
+func NewUserProfitCalculator(tx *gorm.DB, username string, tsMap map[uint]*db.TradingSystem) (*ProfitCalculator, error) {
+	us, err := db.GetUserSetting(tx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	if us == nil || us.BaseCurrency == "" {
+		return NewProfitCalculator(tsMap), nil
+	}
+
+	rates, err := db.GetAllFxRates(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	fx := NewFxConverter(us.BaseCurrency, rates)
+
+	for _, ts := range tsMap {
+		if !fx.CanConvert(ts.CurrencyCode) {
+			return nil, req.NewUnprocessableEntityError("Missing FX rates to convert %v into %v (trading system: %v)", ts.CurrencyCode, us.BaseCurrency, ts.Name)
+		}
+	}
+
+	return NewFxProfitCalculator(tsMap, fx), nil
+}
+
+//=============================================================================
+//===
+//=== Private functions
+//===
+//=============================================================================
+
+func (fx *FxConverter) findPath(currency string, currencies map[string]bool) []*fxStep {
+	if currency == fx.base {
+		return nil
+	}
+
+	if step := fx.findStep(currency, fx.base); step != nil {
+		return []*fxStep{ step }
+	}
+
+	//--- Sorted to always pick the same cross currency
+
+	var list []string
+	for c := range currencies {
+		list = append(list, c)
+	}
+
+	sort.Strings(list)
+
+	for _, cross := range list {
+		step1 := fx.findStep(currency, cross)
+		step2 := fx.findStep(cross, fx.base)
+
+		if step1 != nil && step2 != nil {
+			return []*fxStep{ step1, step2 }
+		}
+	}
+
+	return nil
+}
+
+//=============================================================================
+//--- Returns the step to convert from -> to
+
+func (fx *FxConverter) findStep(from, to string) *fxStep {
+	if s, ok := fx.series[pairKey(from, to)]; ok {
+		return &fxStep{ series: s }
+	}
+
+	if s, ok := fx.series[pairKey(to, from)]; ok {
+		return &fxStep{ series: s, inverse: true }
+	}
+
+	return nil
+}
+
+//=============================================================================
+
+func pairKey(base, quote string) string {
+	return base +"/"+ quote
+}
+
+//=============================================================================
+
+func (s *fxSeries) rateAt(day datatype.IntDate) float64 {
+	i := sort.Search(len(s.days), func(i int) bool {
+		return s.days[i] > day
+	})
+
+	if i == 0 {
+		return s.rates[0]
+	}
+
+	return s.rates[i -1]
+}
+
+//=============================================================================
+
+func (s *fxSeries) Len() int           { return len(s.days) }
+func (s *fxSeries) Less(i, j int) bool { return s.days[i] < s.days[j] }
+
+func (s *fxSeries) Swap(i, j int) {
+	s.days [i], s.days [j] = s.days [j], s.days [i]
+	s.rates[i], s.rates[j] = s.rates[j], s.rates[i]
+}
+
+//=============================================================================
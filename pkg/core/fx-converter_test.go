@@ -0,0 +1,140 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+
+
+package core
+
+import (
+	"math"
+	"testing"
+
+	"github.com/tradalia/core/datatype"
+	"github.com/tradalia/portfolio-trader/pkg/db"
+	"github.com/tradalia/portfolio-trader/pkg/db/dbtest"
+)
+
+//=============================================================================
+
+var fxRates = []db.FxRate{
+	{ Day: 20250108, BaseCurrency: "EUR", QuoteCurrency: "USD", Rate: 1.25 },
+	{ Day: 20250106, BaseCurrency: "EUR", QuoteCurrency: "USD", Rate: 1.10 },
+	{ Day: 20250106, BaseCurrency: "GBP", QuoteCurrency: "EUR", Rate: 1.20 },
+	{ Day: 20250106, BaseCurrency: "USD", QuoteCurrency: "JPY", Rate: 150  },
+	{ Day: 20250106, BaseCurrency: "CHF", QuoteCurrency: "SEK", Rate: 12   },
+}
+
+//=============================================================================
+
+func TestFxConverterRate(t *testing.T) {
+	fx := NewFxConverter("EUR", &fxRates)
+
+	cases := []struct {
+		currency string
+		day      datatype.IntDate
+		expected float64
+	}{
+		{ "",    20250106, 1 },
+		{ "EUR", 20250106, 1 },
+		{ "GBP", 20250106, 1.20 },
+		{ "USD", 20250106, 1 / 1.10 },
+		{ "USD", 20250105, 1 / 1.10 },
+		{ "USD", 20250107, 1 / 1.10 },
+		{ "USD", 20250108, 1 / 1.25 },
+		{ "USD", 20250110, 1 / 1.25 },
+		{ "JPY", 20250106, 1 / 150.0 / 1.10 },
+		{ "JPY", 20250109, 1 / 150.0 / 1.25 },
+		{ "CHF", 20250106, 1 },
+	}
+
+	for _, c := range cases {
+		if rate := fx.Rate(c.currency, c.day); math.Abs(rate - c.expected) > 1e-12 {
+			t.Errorf("Bad rate for '%v' on %v: Expected %v and got %v", c.currency, c.day, c.expected, rate)
+		}
+	}
+}
+
+//=============================================================================
+
+func TestFxConverterCanConvert(t *testing.T) {
+	fx := NewFxConverter("EUR", &fxRates)
+
+	cases := map[string]bool{
+		""   : true,
+		"EUR": true,
+		"GBP": true,
+		"USD": true,
+		"JPY": true,
+		"CHF": false,
+		"SEK": false,
+		"AUD": false,
+	}
+
+	for currency, expected := range cases {
+		if fx.CanConvert(currency) != expected {
+			t.Errorf("Bad conversion check for '%v': Expected %v and got %v", currency, expected, !expected)
+		}
+	}
+
+	fx = NewFxConverter("EUR", nil)
+
+	if fx.CanConvert("USD") {
+		t.Errorf("Bad conversion check without rates: Expected false and got true")
+	}
+}
+
+//=============================================================================
+
+func TestFxProfitCalculator(t *testing.T) {
+	tsMap := map[uint]*db.TradingSystem{
+		1: { Id: 1, CurrencyCode: "USD", CostPerOperation: 5.5, MarginValue: 1100 },
+		2: { Id: 2, CurrencyCode: "EUR", CostPerOperation: 2,   MarginValue: 500  },
+	}
+
+	pc := NewFxProfitCalculator(tsMap, NewFxConverter("EUR", &fxRates))
+
+	trades := &[]db.Trade{
+		dbtest.NewTrade(1, db.TradeTypeLong, dbtest.Time(7, 10), dbtest.Time(7, 15), 110, 1),
+		dbtest.NewTrade(2, db.TradeTypeLong, dbtest.Time(7, 10), dbtest.Time(7, 15), 100, 1),
+	}
+
+	list := pc.ConvertTrades(trades)
+
+	if math.Abs((*list)[0].GrossProfit - 100) > 1e-9 || (*list)[1].GrossProfit != 100 {
+		t.Errorf("Bad converted profits: Expected 100, 100 and got %v, %v", (*list)[0].GrossProfit, (*list)[1].GrossProfit)
+	}
+
+	if (*trades)[0].GrossProfit != 110 {
+		t.Errorf("Bad original profit: Expected 110 and got %v", (*trades)[0].GrossProfit)
+	}
+
+	if net := pc.NetProfit(&(*list)[0]); math.Abs(net - 90) > 1e-9 {
+		t.Errorf("Bad net profit: Expected 90 and got %v", net)
+	}
+
+	if margin := pc.MarginValue(); math.Abs(margin - 1380) > 1e-9 {
+		t.Errorf("Bad margin value: Expected 1380 and got %v", margin)
+	}
+}
+
+//=============================================================================
@@ -97,6 +97,9 @@ func handleMessage(m *msg.Message) bool {
 		if m.Type == msg.TypeUpdate {
 			return updateBrokerProduct(&pbm)
 		}
+	} else if m.Source == msg.SourcePortfolio {
+		//--- Portfolio changes are published by this service. Nothing to do
+		return true
 	}
 
 	slog.Error("Dropping message with unknown source/type!", "source", m.Source, "type", m.Type)
@@ -32,7 +32,9 @@ import (
 
 	"github.com/tradalia/core/datatype"
 	"github.com/tradalia/core/msg"
+	"github.com/tradalia/portfolio-trader/pkg/business/degradation"
 	"github.com/tradalia/portfolio-trader/pkg/business/filter"
+	"github.com/tradalia/portfolio-trader/pkg/business/risk"
 	"github.com/tradalia/portfolio-trader/pkg/consts"
 	"github.com/tradalia/portfolio-trader/pkg/db"
 	"gorm.io/gorm"
@@ -76,6 +78,8 @@ func handleNewTrades(tm *TradeListMessage) bool {
 
 	slog.Info("handleNewTrades: Processing new trades for trading systems", "id", tsId)
 
+	var events []*risk.Event
+
 	err := db.RunInTransaction(func (tx *gorm.DB) error {
 		ts, err := db.GetTradingSystemById(tx, tsId)
 		if err != nil {
@@ -107,6 +111,9 @@ func handleNewTrades(tm *TradeListMessage) bool {
 						err = addNewDailyProfits(tx, ts, dailyProfits, tm.DailyProfits)
 						if err == nil {
 							err = updateTradingSystem(tx, ts, trades, tf)
+							if err == nil {
+								events, err = risk.CheckTradingSystem(tx, ts)
+							}
 						}
 					}
 				}
@@ -116,6 +123,10 @@ func handleNewTrades(tm *TradeListMessage) bool {
 		return err
 	})
 
+	if err == nil {
+		risk.SendEvents(events...)
+	}
+
 	return err == nil
 }
 
@@ -260,6 +271,9 @@ func updateTradingSystem(tx *gorm.DB, ts *db.TradingSystem, trades *[]db.Trade,
 		}
 	}
 
+	degradation.UpdateStatus(ts, trades)
+	degradation.UpdateEnvelopeAction(ts)
+
 	return db.UpdateTradingSystem(tx, ts)
 }
 
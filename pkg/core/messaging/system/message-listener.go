@@ -0,0 +1,106 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+
+
+package system
+
+import (
+	"encoding/json"
+	"log/slog"
+	"strings"
+
+	"github.com/tradalia/core/msg"
+	"github.com/tradalia/portfolio-trader/pkg/business"
+	"github.com/tradalia/portfolio-trader/pkg/db"
+	"gorm.io/gorm"
+)
+
+//=============================================================================
+
+func InitMessageListener() {
+	slog.Info("Starting system message listener...")
+
+	go msg.ReceiveMessages(msg.QuSystemToPortfolio, handleMessage)
+}
+
+//=============================================================================
+
+func handleMessage(m *msg.Message) bool {
+
+	slog.Info("New message received", "source", m.Source, "type", m.Type)
+
+	if m.Source == SourceFxRate {
+		frm := FxRateListMessage{}
+		err := json.Unmarshal(m.Entity, &frm)
+		if err != nil {
+			slog.Error("Dropping badly formatted message!", "entity", string(m.Entity))
+			return true
+		}
+
+		if m.Type == msg.TypeCreate || m.Type == msg.TypeUpdate {
+			return setFxRates(&frm)
+		}
+	}
+
+	slog.Error("Dropping message with unknown source/type!", "source", m.Source, "type", m.Type)
+	return true
+}
+
+//=============================================================================
+
+func setFxRates(frm *FxRateListMessage) bool {
+	var list []db.FxRate
+
+	for _, r := range frm.Rates {
+		rate := db.FxRate{
+			Day          : r.Day,
+			BaseCurrency : strings.ToUpper(r.BaseCurrency),
+			QuoteCurrency: strings.ToUpper(r.QuoteCurrency),
+			Rate         : r.Rate,
+		}
+
+		//--- Bad rates are skipped, otherwise the message would be delivered again and again
+
+		if err := business.ValidateFxRate(&rate); err != nil {
+			slog.Warn("setFxRates: Skipping bad FX rate", "day", r.Day, "base", r.BaseCurrency, "quote", r.QuoteCurrency, "error", err.Error())
+			continue
+		}
+
+		list = append(list, rate)
+	}
+
+	err := db.RunInTransaction(func(tx *gorm.DB) error {
+		return business.SetFxRates(tx, list)
+	})
+
+	if err != nil {
+		slog.Error("setFxRates: Cannot store FX rates", "count", len(list), "error", err.Error())
+	} else {
+		slog.Info("setFxRates: FX rates stored", "count", len(list))
+	}
+
+	return err == nil
+}
+
+//=============================================================================
@@ -0,0 +1,110 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+package core
+
+import (
+	"github.com/tradalia/portfolio-trader/pkg/db"
+	"gorm.io/gorm"
+)
+
+//=============================================================================
+//--- Returns the ids of the portfolio and of all its descendants. Circular
+//--- loops are ignored
+
+func GetPortfolioSubtreeIds(list *[]db.Portfolio, rootId uint) []uint {
+	childMap := map[uint][]uint{}
+	for _, p := range *list {
+		childMap[p.ParentId] = append(childMap[p.ParentId], p.Id)
+	}
+
+	visited := map[uint]bool{}
+	queue   := []uint{ rootId }
+	var ids []uint
+
+	for len(queue) > 0 {
+		curr := queue[0]
+		queue = queue[1:]
+
+		if visited[curr] {
+			continue
+		}
+
+		visited[curr] = true
+		ids   = append(ids,   curr)
+		queue = append(queue, childMap[curr]...)
+	}
+
+	return ids
+}
+
+//=============================================================================
+//--- Returns the ids of the portfolio and of all its ancestors, from the
+//--- portfolio up to the root
+
+func GetPortfolioAncestorIds(list *[]db.Portfolio, id uint) []uint {
+	parentMap := map[uint]uint{}
+	for _, p := range *list {
+		parentMap[p.Id] = p.ParentId
+	}
+
+	visited := map[uint]bool{}
+	var ids []uint
+
+	for id != 0 && !visited[id] {
+		if _, ok := parentMap[id]; !ok {
+			break
+		}
+
+		visited[id] = true
+		ids = append(ids, id)
+		id  = parentMap[id]
+	}
+
+	return ids
+}
+
+//=============================================================================
+//--- Returns the trading systems of the portfolio and of all its descendants
+
+func GetPortfolioTradingSystems(tx *gorm.DB, p *db.Portfolio) (map[uint]*db.TradingSystem, error) {
+	list, err := db.GetPortfoliosByUser(tx, p.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	tsList, err := db.GetTradingSystemsByPortfolioIds(tx, GetPortfolioSubtreeIds(list, p.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	tsMap := map[uint]*db.TradingSystem{}
+	for i := range *tsList {
+		ts := &(*tsList)[i]
+		tsMap[ts.Id] = ts
+	}
+
+	return tsMap, nil
+}
+
+//=============================================================================
@@ -0,0 +1,139 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+
+package envelopeupdater
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/tradalia/portfolio-trader/pkg/app"
+	"github.com/tradalia/portfolio-trader/pkg/business/degradation"
+	"github.com/tradalia/portfolio-trader/pkg/core/process/common"
+	"github.com/tradalia/portfolio-trader/pkg/db"
+	"gorm.io/gorm"
+)
+
+//=============================================================================
+
+const (
+	DefaultPercentile = 95.0
+	DefaultRuns       = 1000
+)
+
+//=============================================================================
+
+func Init(cfg *app.Config) *time.Ticker {
+	ticker := time.NewTicker(24 * time.Hour)
+
+	go func() {
+		//--- Wait 10 secs to allow the system to boot properly
+		time.Sleep(10 * time.Second)
+		run(cfg)
+
+		for range ticker.C {
+			run(cfg)
+		}
+	}()
+
+	return ticker
+}
+
+//=============================================================================
+
+func run(cfg *app.Config) {
+	slog.Info("EnvelopeUpdater: Starting")
+	start := time.Now()
+
+	percentile := cfg.Monitoring.DrawdownPercentile
+	if percentile <= 0 || percentile >= 100 {
+		percentile = DefaultPercentile
+	}
+
+	runs := cfg.Monitoring.DrawdownRuns
+	if runs <= 0 {
+		runs = DefaultRuns
+	}
+
+	list, err := common.GetRunningTradingSystems()
+	if err != nil {
+		slog.Error("EnvelopeUpdater: Cannot get list of running trading systems. Update aborted", "error", err)
+	} else {
+		slog.Info("EnvelopeUpdater: Processing trading systems", "count", len(*list))
+
+		for _, ts := range *list {
+			err = updateTradingSystem(&ts, percentile, runs)
+			if err != nil {
+				slog.Error("EnvelopeUpdater: Cannot update trading system", "id", ts.Id, "error", err)
+			}
+		}
+	}
+
+	duration := time.Now().Sub(start).Seconds()
+	slog.Info("EnvelopeUpdater: Ended", "seconds", duration)
+}
+
+//=============================================================================
+
+func updateTradingSystem(ts *db.TradingSystem, percentile float64, runs int) error {
+	var trades *[]db.Trade
+	var err error
+
+	err = db.RunInTransaction(func (tx *gorm.DB) error {
+		trades, err = db.FindTradesByTradingSystemId(tx, ts.Id)
+		return err
+	})
+
+	if err != nil {
+		return err
+	}
+
+	//--- The simulation can take some time: keep it outside of the transaction
+
+	er := degradation.CheckDrawdownEnvelope(ts, trades, percentile, runs)
+
+	//--- Reload the trading system: it could have been changed in the meantime
+
+	return db.RunInTransaction(func (tx *gorm.DB) error {
+		curr, err := db.GetTradingSystemById(tx, ts.Id)
+		if err != nil {
+			return err
+		}
+
+		if curr == nil || !curr.Running {
+			return nil
+		}
+
+		wasExceeded := curr.EnvelopeExceeded
+		degradation.UpdateEnvelope(curr, er)
+
+		if curr.EnvelopeExceeded && !wasExceeded {
+			slog.Warn("EnvelopeUpdater: Live drawdown exceeds the Monte Carlo envelope", "id", curr.Id, "depthPerc", curr.EnvelopeDepthPerc, "durationPerc", curr.EnvelopeDurPerc)
+		}
+
+		return db.UpdateTradingSystemEnvelope(tx, curr)
+	})
+}
+
+//=============================================================================
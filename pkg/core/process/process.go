@@ -26,6 +26,7 @@ package process
 
 import (
 	"github.com/tradalia/portfolio-trader/pkg/app"
+	"github.com/tradalia/portfolio-trader/pkg/core/process/envelopeupdater"
 	"github.com/tradalia/portfolio-trader/pkg/core/process/statsupdater"
 	"github.com/tradalia/portfolio-trader/pkg/core/process/statusupdater"
 )
@@ -35,6 +36,7 @@ import (
 func Init(cfg *app.Config) {
 	statusupdater.Init(cfg)
 	statsupdater .Init(cfg)
+	envelopeupdater.Init(cfg)
 }
 
 //=============================================================================
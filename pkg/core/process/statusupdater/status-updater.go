@@ -26,7 +26,10 @@ package statusupdater
 
 import (
 	"github.com/tradalia/portfolio-trader/pkg/app"
+	"github.com/tradalia/portfolio-trader/pkg/business/degradation"
+	"github.com/tradalia/portfolio-trader/pkg/business/risk"
 	"github.com/tradalia/portfolio-trader/pkg/consts"
+	"github.com/tradalia/portfolio-trader/pkg/core/process/common"
 	"github.com/tradalia/portfolio-trader/pkg/db"
 	"gorm.io/gorm"
 	"log/slog"
@@ -71,6 +74,9 @@ func run(cfg *app.Config) {
 		}
 	}
 
+	checkDegradation()
+	checkRiskLimits()
+
 	duration := time.Now().Sub(start).Seconds()
 	slog.Info("StatusUpdater: Ended", "seconds", duration)
 }
@@ -92,7 +98,7 @@ func GetTradingSystemsInIdle() (*[]db.TradingSystem, error){
 //=============================================================================
 
 func updateTradingSystem(ts *db.TradingSystem) error {
-	if ts.Status == db.TsStatusRunning {
+	if ts.Status == db.TsStatusRunning || ts.Status == db.TsStatusDegraded {
 		ts.Status = db.TsStatusIdle
 	} else if ts.Status == db.TsStatusIdle {
 		brokenDate := time.Now().Add(-time.Hour * 24 * time.Duration(consts.BrokenDays))
@@ -111,3 +117,100 @@ func updateTradingSystem(ts *db.TradingSystem) error {
 }
 
 //=============================================================================
+
+func checkDegradation() {
+	list, err := common.GetRunningTradingSystems()
+	if err != nil {
+		slog.Error("StatusUpdater: Cannot get list of running trading systems. Degradation check aborted", "error", err)
+		return
+	}
+
+	slog.Info("StatusUpdater: Checking degradation of running trading systems", "count", len(*list))
+
+	for _, ts := range *list {
+		err = checkTradingSystemDegradation(ts.Id)
+		if err != nil {
+			slog.Error("StatusUpdater: Cannot check degradation of trading system", "id", ts.Id, "error", err)
+		}
+	}
+}
+
+//=============================================================================
+//--- The trading system is reloaded inside the transaction, so that changes
+//--- made by trades arriving in the meantime are not overwritten
+
+func checkTradingSystemDegradation(tsId uint) error {
+	return db.RunInTransaction(func (tx *gorm.DB) error {
+		ts, err := db.GetTradingSystemById(tx, tsId)
+		if err != nil {
+			return err
+		}
+
+		if ts == nil || !ts.Running {
+			return nil
+		}
+
+		trades, err := db.FindTradesByTradingSystemId(tx, ts.Id)
+		if err != nil {
+			return err
+		}
+
+		oldStatus := ts.Status
+		oldStat   := ts.DegradationStat
+
+		degradation.UpdateStatus(ts, trades)
+
+		if ts.Status == oldStatus && ts.DegradationStat == oldStat {
+			return nil
+		}
+
+		if ts.Status == db.TsStatusDegraded && oldStatus != db.TsStatusDegraded {
+			slog.Warn("StatusUpdater: Trading system is degraded", "id", ts.Id, "statistic", ts.DegradationStat, "limit", ts.DegradationLimit)
+		}
+
+		return db.UpdateTradingSystem(tx, ts)
+	})
+}
+
+//=============================================================================
+
+func checkRiskLimits() {
+	list, err := getEnabledRiskLimits()
+	if err != nil {
+		slog.Error("StatusUpdater: Cannot get list of risk limits. Risk check aborted", "error", err)
+		return
+	}
+
+	slog.Info("StatusUpdater: Checking portfolio risk limits", "count", len(*list))
+
+	for _, rl := range *list {
+		var st *risk.Status
+
+		err = db.RunInTransaction(func (tx *gorm.DB) error {
+			st, err = risk.CheckPortfolio(tx, &rl)
+			return err
+		})
+
+		if err != nil {
+			slog.Error("StatusUpdater: Cannot check risk limits of portfolio", "portfolioId", rl.PortfolioId, "error", err)
+		} else if st != nil {
+			risk.SendEvents(st.Event)
+		}
+	}
+}
+
+//=============================================================================
+
+func getEnabledRiskLimits() (*[]db.PortfolioRiskLimit, error){
+	var list *[]db.PortfolioRiskLimit
+	var err error
+
+	err = db.RunInTransaction(func (tx *gorm.DB) error {
+		list, err = db.GetEnabledRiskLimits(tx)
+		return err
+	})
+
+	return list,err
+}
+
+//=============================================================================
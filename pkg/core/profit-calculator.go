@@ -0,0 +1,206 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+
+
+package core
+
+import (
+	"time"
+
+	"github.com/tradalia/core/datatype"
+	"github.com/tradalia/portfolio-trader/pkg/db"
+)
+
+//=============================================================================
+//--- Calculates the profits of trades belonging to different trading systems,
+//--- each one with its own costs. With a converter, values are expressed in
+//--- its base currency: trades and returns must be converted first (using
+//--- ConvertTrades and ConvertDailyReturns) while costs and margins are
+//--- converted by the calculator
+
+type ProfitCalculator struct {
+	tsMap map[uint]*db.TradingSystem
+	fx    *FxConverter
+}
+
+//=============================================================================
+
+func NewProfitCalculator(tsMap map[uint]*db.TradingSystem) *ProfitCalculator {
+	return &ProfitCalculator{
+		tsMap: tsMap,
+	}
+}
+
+//=============================================================================
+
+func NewFxProfitCalculator(tsMap map[uint]*db.TradingSystem, fx *FxConverter) *ProfitCalculator {
+	return &ProfitCalculator{
+		tsMap: tsMap,
+		fx   : fx,
+	}
+}
+
+//=============================================================================
+//--- Returns an empty string if amounts are in the currency of each system
+
+func (pc *ProfitCalculator) Currency() string {
+	if pc.fx == nil {
+		return ""
+	}
+
+	return pc.fx.BaseCurrency()
+}
+
+//=============================================================================
+
+func (pc *ProfitCalculator) GrossProfit(tr *db.Trade) float64 {
+	return tr.GrossProfit
+}
+
+//=============================================================================
+
+func (pc *ProfitCalculator) NetProfit(tr *db.Trade) float64 {
+	return tr.GrossProfit - 2 * pc.TradeCost(tr)
+}
+
+//=============================================================================
+
+func (pc *ProfitCalculator) CostPerOperation(tsId uint) float64 {
+	ts, ok := pc.tsMap[tsId]
+	if !ok {
+		return 0
+	}
+
+	return ts.CostPerOperation
+}
+
+//=============================================================================
+//--- Cost per operation converted at the exit date of the trade
+
+func (pc *ProfitCalculator) TradeCost(tr *db.Trade) float64 {
+	return pc.CostPerOperation(tr.TradingSystemId) * pc.rate(tr.TradingSystemId, datatype.ToIntDate(tr.ExitDate))
+}
+
+//=============================================================================
+
+func (pc *ProfitCalculator) NetDailyReturn(dr *db.DailyReturn) float64 {
+	cost := pc.CostPerOperation(dr.TradingSystemId) * pc.rate(dr.TradingSystemId, dr.Day)
+	return dr.GrossProfit - 2 * cost * float64(dr.Trades)
+}
+
+//=============================================================================
+
+func (pc *ProfitCalculator) NetProfits(trades *[]db.Trade, tradeType string) *[]float64 {
+	netSlice := []float64{}
+
+	for i := range *trades {
+		tr := &(*trades)[i]
+		if tradeType == db.TradeTypeAll || tr.TradeType == tradeType {
+			netSlice = append(netSlice, pc.NetProfit(tr))
+		}
+	}
+
+	return &netSlice
+}
+
+//=============================================================================
+//--- Margin converted at the most recent rate
+
+func (pc *ProfitCalculator) Margin(tsId uint) float64 {
+	ts, ok := pc.tsMap[tsId]
+	if !ok {
+		return 0
+	}
+
+	return ts.MarginValue * pc.rate(tsId, datatype.Today(time.UTC))
+}
+
+//=============================================================================
+
+func (pc *ProfitCalculator) MarginValue() float64 {
+	margin := 0.0
+
+	for id := range pc.tsMap {
+		margin += pc.Margin(id)
+	}
+
+	return margin
+}
+
+//=============================================================================
+//--- Returns a copy of the trades with profits converted at the exit date
+
+func (pc *ProfitCalculator) ConvertTrades(trades *[]db.Trade) *[]db.Trade {
+	if pc.fx == nil || trades == nil {
+		return trades
+	}
+
+	list := make([]db.Trade, len(*trades))
+
+	for i, tr := range *trades {
+		tr.GrossProfit *= pc.rate(tr.TradingSystemId, datatype.ToIntDate(tr.ExitDate))
+		list[i] = tr
+	}
+
+	return &list
+}
+
+//=============================================================================
+//--- Returns a copy of the daily returns with profits converted at their day
+
+func (pc *ProfitCalculator) ConvertDailyReturns(returns *[]db.DailyReturn) *[]db.DailyReturn {
+	if pc.fx == nil || returns == nil {
+		return returns
+	}
+
+	list := make([]db.DailyReturn, len(*returns))
+
+	for i, dr := range *returns {
+		dr.GrossProfit *= pc.rate(dr.TradingSystemId, dr.Day)
+		list[i] = dr
+	}
+
+	return &list
+}
+
+//=============================================================================
+//===
+//=== Private methods
+//===
+//=============================================================================
+
+func (pc *ProfitCalculator) rate(tsId uint, day datatype.IntDate) float64 {
+	if pc.fx == nil {
+		return 1
+	}
+
+	ts, ok := pc.tsMap[tsId]
+	if !ok {
+		return 1
+	}
+
+	return pc.fx.Rate(ts.CurrencyCode, day)
+}
+
+//=============================================================================
@@ -0,0 +1,69 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+
+package stats
+
+import (
+	"math/rand"
+)
+
+//=============================================================================
+
+type Interval struct {
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
+}
+
+//=============================================================================
+//--- Percentile bootstrap: the data is resampled with replacement 'runs' times
+//--- and the interval is taken from the distribution of the statistic. The
+//--- caller provides the random source, to get reproducible intervals
+
+func Bootstrap(data []float64, runs int, confidence float64, statistic func([]float64) float64, rng *rand.Rand) Interval {
+	size := len(data)
+	if size == 0 || runs <= 0 {
+		return Interval{}
+	}
+
+	values := make([]float64, runs)
+	sample := make([]float64, size)
+
+	for i := 0; i < runs; i++ {
+		for j := 0; j < size; j++ {
+			sample[j] = data[rng.Intn(size)]
+		}
+
+		values[i] = statistic(sample)
+	}
+
+	tail := (100 - confidence) / 2
+	perc := NewPercentile(values)
+
+	return Interval{
+		Lower: perc.Get(tail),
+		Upper: perc.Get(100 - tail),
+	}
+}
+
+//=============================================================================
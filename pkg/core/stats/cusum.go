@@ -0,0 +1,58 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+
+package stats
+
+import (
+	"math"
+)
+
+//=============================================================================
+//===
+//=== CUSUM control chart
+//===
+//=============================================================================
+//--- Lower one-sided CUSUM on the standardized values. It accumulates the
+//--- deviations below the target mean exceeding the allowance k (expressed in
+//--- standard deviations) and it is used to detect a downward shift of the mean.
+//--- Returns the statistic after each value
+
+func LowerCusum(data []float64, mean, stdDev, k float64) []float64 {
+	res := make([]float64, len(data))
+
+	if stdDev == 0 || math.IsNaN(stdDev) {
+		return res
+	}
+
+	s := 0.0
+
+	for i, v := range data {
+		s = math.Max(0, s + (mean - v) / stdDev - k)
+		res[i] = s
+	}
+
+	return res
+}
+
+//=============================================================================
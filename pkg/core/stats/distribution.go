@@ -0,0 +1,227 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+
+package stats
+
+import (
+	"math"
+)
+
+//=============================================================================
+//===
+//=== Student's t distribution
+//===
+//=============================================================================
+
+func StudentTCdf(t float64, df float64) float64 {
+	if math.IsNaN(t) || df <= 0 {
+		return math.NaN()
+	}
+
+	if math.IsInf(t, 1) {
+		return 1
+	}
+
+	if math.IsInf(t, -1) {
+		return 0
+	}
+
+	x    := df / (df + t*t)
+	tail := 0.5 * RegIncompleteBeta(df/2, 0.5, x)
+
+	if t > 0 {
+		return 1 - tail
+	}
+
+	return tail
+}
+
+//=============================================================================
+//--- One-sided test of the hypothesis mean > 0. Returns the t statistic and
+//--- its p-value
+
+func TTest(data []float64) (float64, float64) {
+	count := len(data)
+	if count < 2 {
+		return 0, 1
+	}
+
+	mean   := Mean(data)
+	stdDev := SampleStdDev(data, mean)
+	if stdDev == 0 {
+		if mean > 0 {
+			return math.Inf(1), 0
+		}
+
+		return 0, 1
+	}
+
+	tStat := TStatistic(mean, stdDev, count)
+	pValue:= 1 - StudentTCdf(tStat, float64(count -1))
+
+	return tStat, pValue
+}
+
+//=============================================================================
+//--- Minimum number of samples needed to make mean > 0 significant at the
+//--- given alpha, assuming the same mean and (sample) standard deviation.
+//--- Returns -1 if the mean is not positive or the count exceeds maxCount
+
+func MinSamplesForSignificance(mean, stdDev, alpha float64, maxCount int) int {
+	if mean <= 0 || math.IsNaN(mean) || math.IsNaN(stdDev) {
+		return -1
+	}
+
+	if stdDev == 0 {
+		return 2
+	}
+
+	//--- The normal approximation gives a lower bound because t quantiles are larger than z ones
+
+	z := NormalQuantile(1 - alpha)
+	n := int(math.Ceil(math.Pow(z * stdDev / mean, 2)))
+	if n < 2 {
+		n = 2
+	}
+
+	for ; n <= maxCount; n++ {
+		tStat := TStatistic(mean, stdDev, n)
+		if 1 - StudentTCdf(tStat, float64(n -1)) <= alpha {
+			return n
+		}
+	}
+
+	return -1
+}
+
+//=============================================================================
+//===
+//=== Normal distribution
+//===
+//=============================================================================
+
+func NormalCdf(x float64) float64 {
+	return 0.5 * math.Erfc(-x / math.Sqrt2)
+}
+
+//=============================================================================
+
+func NormalQuantile(p float64) float64 {
+	return -math.Sqrt2 * math.Erfcinv(2 * p)
+}
+
+//=============================================================================
+//===
+//=== Regularized incomplete beta function (continued fraction, Numerical Recipes)
+//===
+//=============================================================================
+
+func RegIncompleteBeta(a, b, x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+
+	if x >= 1 {
+		return 1
+	}
+
+	lab, _ := math.Lgamma(a + b)
+	la,  _ := math.Lgamma(a)
+	lb,  _ := math.Lgamma(b)
+
+	front := math.Exp(lab - la - lb + a*math.Log(x) + b*math.Log(1-x))
+
+	if x < (a+1)/(a+b+2) {
+		return front * betaContinuedFraction(a, b, x) / a
+	}
+
+	return 1 - front * betaContinuedFraction(b, a, 1-x) / b
+}
+
+//=============================================================================
+//===
+//=== Private functions
+//===
+//=============================================================================
+
+func betaContinuedFraction(a, b, x float64) float64 {
+	const maxIterations = 300
+	const epsilon       = 3e-14
+	const fpMin         = 1e-300
+
+	qab := a + b
+	qap := a + 1
+	qam := a - 1
+	c   := 1.0
+	d   := 1 - qab*x/qap
+
+	if math.Abs(d) < fpMin {
+		d = fpMin
+	}
+
+	d  = 1 / d
+	h := d
+
+	for m := 1; m <= maxIterations; m++ {
+		mf := float64(m)
+		m2 := 2 * mf
+
+		//--- Even step
+
+		aa := mf * (b - mf) * x / ((qam + m2) * (a + m2))
+		d = 1 + aa*d
+		if math.Abs(d) < fpMin {
+			d = fpMin
+		}
+		c = 1 + aa/c
+		if math.Abs(c) < fpMin {
+			c = fpMin
+		}
+		d  = 1 / d
+		h *= d * c
+
+		//--- Odd step
+
+		aa = -(a + mf) * (qab + mf) * x / ((a + m2) * (qap + m2))
+		d = 1 + aa*d
+		if math.Abs(d) < fpMin {
+			d = fpMin
+		}
+		c = 1 + aa/c
+		if math.Abs(c) < fpMin {
+			c = fpMin
+		}
+		d  = 1 / d
+		delta := d * c
+		h *= delta
+
+		if math.Abs(delta - 1) < epsilon {
+			break
+		}
+	}
+
+	return h
+}
+
+//=============================================================================
@@ -0,0 +1,159 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+
+package stats
+
+import (
+	"math"
+	"slices"
+)
+
+//=============================================================================
+//===
+//=== Two-sample tests
+//===
+//=============================================================================
+//--- Welch's t-test, one-sided on the hypothesis mean(x) < mean(y). Returns
+//--- the t statistic and its p-value
+
+func WelchTTest(x, y []float64) (float64, float64) {
+	nx := float64(len(x))
+	ny := float64(len(y))
+
+	if nx < 2 || ny < 2 {
+		return 0, 1
+	}
+
+	meanX := Mean(x)
+	meanY := Mean(y)
+	varX  := math.Pow(SampleStdDev(x, meanX), 2) / nx
+	varY  := math.Pow(SampleStdDev(y, meanY), 2) / ny
+
+	if varX + varY == 0 {
+		if meanX < meanY {
+			return math.Inf(-1), 0
+		}
+
+		return 0, 1
+	}
+
+	tStat := (meanX - meanY) / math.Sqrt(varX + varY)
+	df    := math.Pow(varX + varY, 2) / (varX*varX/(nx -1) + varY*varY/(ny -1))
+
+	return tStat, StudentTCdf(tStat, df)
+}
+
+//=============================================================================
+//--- Pooled z-test, one-sided on the hypothesis p1 < p2 where p = successes/count.
+//--- Returns the z statistic and its p-value
+
+func TwoProportionZTest(succ1, count1, succ2, count2 int) (float64, float64) {
+	if count1 == 0 || count2 == 0 {
+		return 0, 1
+	}
+
+	n1 := float64(count1)
+	n2 := float64(count2)
+	p1 := float64(succ1) / n1
+	p2 := float64(succ2) / n2
+	p  := float64(succ1 + succ2) / (n1 + n2)
+
+	se := math.Sqrt(p * (1 - p) * (1/n1 + 1/n2))
+	if se == 0 {
+		return 0, 1
+	}
+
+	z := (p1 - p2) / se
+
+	return z, NormalCdf(z)
+}
+
+//=============================================================================
+//--- Two-sample Kolmogorov-Smirnov test. Returns the D statistic and the
+//--- asymptotic p-value (Numerical Recipes)
+
+func KolmogorovSmirnovTest(x, y []float64) (float64, float64) {
+	if len(x) == 0 || len(y) == 0 {
+		return 0, 1
+	}
+
+	sx := slices.Clone(x)
+	sy := slices.Clone(y)
+	slices.Sort(sx)
+	slices.Sort(sy)
+
+	nx := float64(len(sx))
+	ny := float64(len(sy))
+	d  := 0.0
+	i, j := 0, 0
+
+	for i < len(sx) && j < len(sy) {
+		value := math.Min(sx[i], sy[j])
+
+		for i < len(sx) && sx[i] == value {
+			i++
+		}
+
+		for j < len(sy) && sy[j] == value {
+			j++
+		}
+
+		diff := math.Abs(float64(i)/nx - float64(j)/ny)
+		if diff > d {
+			d = diff
+		}
+	}
+
+	ne     := math.Sqrt(nx * ny / (nx + ny))
+	lambda := (ne + 0.12 + 0.11/ne) * d
+
+	return d, kolmogorovQ(lambda)
+}
+
+//=============================================================================
+//--- Complementary cumulative Kolmogorov distribution
+
+func kolmogorovQ(lambda float64) float64 {
+	if lambda < 1e-3 {
+		return 1
+	}
+
+	sum  := 0.0
+	sign := 1.0
+
+	for k := 1; k <= 100; k++ {
+		term := sign * math.Exp(-2 * float64(k*k) * lambda * lambda)
+		sum  += term
+
+		if math.Abs(term) < 1e-10 {
+			break
+		}
+
+		sign = -sign
+	}
+
+	return math.Max(0, math.Min(1, 2 * sum))
+}
+
+//=============================================================================
@@ -59,3 +59,16 @@ func (p *Percentile) Get(percentile float64) float64 {
 }
 
 //=============================================================================
+//--- Returns the percentage of values strictly lower than the given one
+
+func (p *Percentile) Rank(value float64) float64 {
+	if len(p.data) == 0 {
+		return 0
+	}
+
+	idx, _ := slices.BinarySearch(p.data, value)
+
+	return float64(idx) * 100 / float64(len(p.data))
+}
+
+//=============================================================================
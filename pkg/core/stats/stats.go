@@ -32,6 +32,10 @@ import (
 
 //=============================================================================
 
+const TradingDaysPerYear = 252
+
+//=============================================================================
+
 func Mean[T float64|int](data []T) float64 {
 	if data == nil || len(data) == 0 {
 		return math.NaN()
@@ -83,6 +87,112 @@ func StdDev(data []float64, mean float64) float64 {
 
 //=============================================================================
 
+func SampleStdDev(data []float64, mean float64) float64 {
+	if len(data) < 2 {
+		return math.NaN()
+	}
+
+	sum := 0.0
+
+	for _, v := range data {
+		diff := v - mean
+		sum += diff * diff
+	}
+
+	return math.Sqrt(sum/float64(len(data) -1))
+}
+
+//=============================================================================
+//--- Population covariance. Both slices must have the same length
+
+func Covariance(x, y []float64) float64 {
+	if len(x) == 0 || len(x) != len(y) {
+		return math.NaN()
+	}
+
+	meanX := Mean(x)
+	meanY := Mean(y)
+	sum   := 0.0
+
+	for i := range x {
+		sum += (x[i] - meanX) * (y[i] - meanY)
+	}
+
+	return sum / float64(len(x))
+}
+
+//=============================================================================
+//--- Pearson correlation coefficient. Returns 0 if one of the series is constant
+
+func Correlation(x, y []float64) float64 {
+	if len(x) == 0 || len(x) != len(y) {
+		return math.NaN()
+	}
+
+	stdX := StdDev(x, Mean(x))
+	stdY := StdDev(y, Mean(y))
+
+	if stdX == 0 || stdY == 0 {
+		return 0
+	}
+
+	return Covariance(x, y) / (stdX * stdY)
+}
+
+//=============================================================================
+//--- Spearman rank correlation: Pearson correlation of the ranks, using the
+//--- average rank for ties
+
+func SpearmanCorrelation(x, y []float64) float64 {
+	if len(x) == 0 || len(x) != len(y) {
+		return math.NaN()
+	}
+
+	return Correlation(Ranks(x), Ranks(y))
+}
+
+//=============================================================================
+//--- Ranks start from 1. Tied values get the average of their ranks
+
+func Ranks(data []float64) []float64 {
+	index := make([]int, len(data))
+	for i := range index {
+		index[i] = i
+	}
+
+	slices.SortStableFunc(index, func(a, b int) int {
+		if data[a] < data[b] {
+			return -1
+		}
+
+		if data[a] > data[b] {
+			return 1
+		}
+
+		return 0
+	})
+
+	ranks := make([]float64, len(data))
+
+	for i := 0; i < len(index); {
+		j := i
+		for j+1 < len(index) && data[index[j+1]] == data[index[i]] {
+			j++
+		}
+
+		rank := float64(i + j) / 2 + 1
+		for k := i; k <= j; k++ {
+			ranks[index[k]] = rank
+		}
+
+		i = j + 1
+	}
+
+	return ranks
+}
+
+//=============================================================================
+
 func SharpeRatio(average, stdDev float64) float64{
 	if average == math.NaN() {
 		return math.NaN()
@@ -99,6 +209,53 @@ func SharpeRatio(average, stdDev float64) float64{
 	return average/stdDev
 }
 
+//=============================================================================
+//--- Sharpe ratio of daily returns, annualized over TradingDaysPerYear. Returns
+//--- 0 when the returns have no variance
+
+func AnnualizedSharpeRatio(mean, stdDev float64) float64 {
+	if stdDev == 0 || math.IsNaN(stdDev) {
+		return 0
+	}
+
+	return mean / stdDev * math.Sqrt(TradingDaysPerYear)
+}
+
+//=============================================================================
+//--- Standard deviation of daily returns, annualized over TradingDaysPerYear
+
+func AnnualizedStdDev(stdDev float64) float64 {
+	return stdDev * math.Sqrt(TradingDaysPerYear)
+}
+
+//=============================================================================
+//--- Compound annual growth rate, in percentage. A final value that is not
+//--- positive means that all the capital has been lost
+
+func Cagr(initial, final, years float64) float64 {
+	if years <= 0 || initial <= 0 {
+		return 0
+	}
+
+	if final <= 0 {
+		return -100
+	}
+
+	return (math.Pow(final / initial, 1 / years) - 1) * 100
+}
+
+//=============================================================================
+//--- T statistic of the mean against zero. stdDev must be the sample standard
+//--- deviation (n-1)
+
+func TStatistic(mean, stdDev float64, count int) float64 {
+	if count < 2 || stdDev == 0 || math.IsNaN(stdDev) {
+		return 0
+	}
+
+	return mean / (stdDev / math.Sqrt(float64(count)))
+}
+
 //=============================================================================
 
 func Skewness(mean, median, stdDev float64) float64 {
@@ -26,6 +26,7 @@ package stats
 
 import (
 	"math"
+	"math/rand"
 	"testing"
 )
 
@@ -47,3 +48,259 @@ func TestSharpeRatio(t *testing.T) {
 }
 
 //=============================================================================
+
+func TestTStatistic(t *testing.T) {
+	ts := TStatistic(2, 4, 16)
+
+	if ts != 2 {
+		t.Errorf("Bad t statistic: Expected 2 and got %v", ts)
+	}
+
+	ts = TStatistic(2, 4, 1)
+
+	if ts != 0 {
+		t.Errorf("Bad t statistic with a single value: Expected 0 and got %v", ts)
+	}
+}
+
+//=============================================================================
+
+func TestCorrelation(t *testing.T) {
+	x := []float64{ 1, 2, 3, 4, 5 }
+	y := []float64{ 2, 4, 6, 8, 10 }
+	z := []float64{ 5, 4, 3, 2, 1 }
+
+	if cov := Covariance(x, y); math.Abs(cov - 4) > 1e-9 {
+		t.Errorf("Bad covariance: Expected 4 and got %v", cov)
+	}
+
+	if cor := Correlation(x, y); math.Abs(cor - 1) > 1e-9 {
+		t.Errorf("Bad correlation: Expected 1 and got %v", cor)
+	}
+
+	if cor := Correlation(x, z); math.Abs(cor + 1) > 1e-9 {
+		t.Errorf("Bad correlation: Expected -1 and got %v", cor)
+	}
+
+	if cor := Correlation(x, []float64{ 3, 3, 3, 3, 3 }); cor != 0 {
+		t.Errorf("Bad correlation with constant series: Expected 0 and got %v", cor)
+	}
+}
+
+//=============================================================================
+
+func TestSpearmanCorrelation(t *testing.T) {
+	ranks := Ranks([]float64{ 10, 30, 20, 20 })
+	exp   := []float64{ 1, 4, 2.5, 2.5 }
+
+	for i := range exp {
+		if ranks[i] != exp[i] {
+			t.Errorf("Bad rank at %v: Expected %v and got %v", i, exp[i], ranks[i])
+		}
+	}
+
+	//--- Monotonic but not linear
+
+	x := []float64{ 1, 2, 3, 4, 5 }
+	y := []float64{ 1, 4, 9, 16, 100 }
+
+	if cor := SpearmanCorrelation(x, y); math.Abs(cor - 1) > 1e-9 {
+		t.Errorf("Bad spearman correlation: Expected 1 and got %v", cor)
+	}
+
+	if cor := Correlation(x, y); cor >= 1 {
+		t.Errorf("Bad pearson correlation: Expected less than 1 and got %v", cor)
+	}
+}
+
+//=============================================================================
+
+func TestStudentTCdf(t *testing.T) {
+	cdf := StudentTCdf(0, 10)
+
+	if math.Abs(cdf - 0.5) > 1e-9 {
+		t.Errorf("Bad t cdf: Expected 0.5 and got %v", cdf)
+	}
+
+	cdf = StudentTCdf(2.228, 10)
+
+	if math.Abs(cdf - 0.975) > 1e-3 {
+		t.Errorf("Bad t cdf: Expected ~0.975 and got %v", cdf)
+	}
+
+	cdf = StudentTCdf(-1.812, 10)
+
+	if math.Abs(cdf - 0.05) > 1e-3 {
+		t.Errorf("Bad t cdf: Expected ~0.05 and got %v", cdf)
+	}
+}
+
+//=============================================================================
+
+func TestTTest(t *testing.T) {
+	tStat, pValue := TTest(prices)
+
+	if tStat < 2.519 || tStat > 2.520 {
+		t.Errorf("Bad t statistic: Expected ~2.5197 and got %v", tStat)
+	}
+
+	if pValue < 0.0142 || pValue > 0.0143 {
+		t.Errorf("Bad p-value: Expected ~0.01425 and got %v", pValue)
+	}
+}
+
+//=============================================================================
+
+func TestMinSamplesForSignificance(t *testing.T) {
+	n := MinSamplesForSignificance(1, 10, 0.05, 100000)
+
+	if n < 270 || n > 275 {
+		t.Errorf("Bad min samples: Expected ~272 and got %v", n)
+	}
+
+	n = MinSamplesForSignificance(-1, 10, 0.05, 100000)
+
+	if n != -1 {
+		t.Errorf("Bad min samples with negative mean: Expected -1 and got %v", n)
+	}
+}
+
+//=============================================================================
+
+func TestBootstrap(t *testing.T) {
+	rng  := rand.New(rand.NewSource(1))
+	data := []float64{ 3, 3, 3, 3 }
+	ci   := Bootstrap(data, 100, 95, Mean[float64], rng)
+
+	if ci.Lower != 3 || ci.Upper != 3 {
+		t.Errorf("Bad bootstrap interval: Expected [3,3] and got %v", ci)
+	}
+
+	ci = Bootstrap(prices, 1000, 95, Mean[float64], rng)
+
+	if ci.Lower > 4.02 || ci.Upper < 4.02 {
+		t.Errorf("Bad bootstrap interval: Expected to contain the mean and got %v", ci)
+	}
+}
+
+//=============================================================================
+
+func TestWelchTTest(t *testing.T) {
+	x := []float64{ 1, 2, 3, 4, 5 }
+	y := []float64{ 6, 7, 8, 9, 10 }
+
+	tStat, p := WelchTTest(x, y)
+
+	if math.Abs(tStat + 5) > 1e-9 {
+		t.Errorf("Bad Welch t statistic: Expected -5 and got %v", tStat)
+	}
+
+	if p > 0.001 || p <= 0 {
+		t.Errorf("Bad Welch p-value: got %v", p)
+	}
+
+	_, p = WelchTTest(y, x)
+
+	if p < 0.99 {
+		t.Errorf("Bad Welch p-value when x > y: got %v", p)
+	}
+}
+
+//=============================================================================
+
+func TestTwoProportionZTest(t *testing.T) {
+	z, p := TwoProportionZTest(30, 100, 50, 100)
+
+	if math.Abs(z + 2.8868) > 1e-3 {
+		t.Errorf("Bad z statistic: Expected -2.8868 and got %v", z)
+	}
+
+	if math.Abs(p - 0.00195) > 1e-4 {
+		t.Errorf("Bad z-test p-value: Expected 0.00195 and got %v", p)
+	}
+}
+
+//=============================================================================
+
+func TestKolmogorovSmirnovTest(t *testing.T) {
+	d, p := KolmogorovSmirnovTest(prices, prices)
+
+	if d != 0 || p != 1 {
+		t.Errorf("Bad KS test on same sample: got D=%v p=%v", d, p)
+	}
+
+	x := []float64{ 1, 2, 3, 4, 5, 6, 7, 8, 9, 10 }
+	y := []float64{ 11, 12, 13, 14, 15, 16, 17, 18, 19, 20 }
+
+	d, p = KolmogorovSmirnovTest(x, y)
+
+	if d != 1 {
+		t.Errorf("Bad KS statistic on disjoint samples: Expected 1 and got %v", d)
+	}
+
+	if p > 0.001 {
+		t.Errorf("Bad KS p-value on disjoint samples: got %v", p)
+	}
+}
+
+//=============================================================================
+
+func TestLowerCusum(t *testing.T) {
+	data   := []float64{ 0, -2, -2, 1, 3, -1.5 }
+	cusum  := LowerCusum(data, 0, 1, 0.5)
+	expect := []float64{ 0, 1.5, 3, 1.5, 0, 1 }
+
+	for i := range expect {
+		if math.Abs(cusum[i] - expect[i]) > 1e-9 {
+			t.Errorf("Bad CUSUM at %d: Expected %v and got %v", i, expect[i], cusum[i])
+		}
+	}
+}
+
+//=============================================================================
+
+func TestPercentileRank(t *testing.T) {
+	p := NewPercentile([]float64{ 5, 1, 4, 2, 3, 3, 6, 7, 8, 9 })
+
+	if r := p.Rank(3); r != 20 {
+		t.Errorf("Bad rank: Expected 20 and got %v", r)
+	}
+
+	if r := p.Rank(10); r != 100 {
+		t.Errorf("Bad rank: Expected 100 and got %v", r)
+	}
+
+	if r := p.Rank(0); r != 0 {
+		t.Errorf("Bad rank: Expected 0 and got %v", r)
+	}
+}
+
+//=============================================================================
+
+func TestCagr(t *testing.T) {
+	if cagr := Cagr(1000, 1210, 2); math.Abs(cagr - 10) > 1e-9 {
+		t.Errorf("Bad cagr: Expected 10 and got %v", cagr)
+	}
+
+	if cagr := Cagr(1000, -50, 2); cagr != -100 {
+		t.Errorf("Bad cagr: Expected -100 and got %v", cagr)
+	}
+
+	if cagr := Cagr(1000, 1500, 0); cagr != 0 {
+		t.Errorf("Bad cagr: Expected 0 and got %v", cagr)
+	}
+}
+
+//=============================================================================
+
+func TestAnnualizedSharpeRatio(t *testing.T) {
+	if sr := AnnualizedSharpeRatio(1, 2); math.Abs(sr - 0.5 * math.Sqrt(252)) > 1e-9 {
+		t.Errorf("Bad sharpe ratio: Expected %v and got %v", 0.5 * math.Sqrt(252), sr)
+	}
+
+	if sr := AnnualizedSharpeRatio(1, 0); sr != 0 {
+		t.Errorf("Bad sharpe ratio: Expected 0 and got %v", sr)
+	}
+}
+
+//=============================================================================
@@ -28,6 +28,7 @@ import (
 	"math"
 	"time"
 
+	"github.com/tradalia/core/datatype"
 	"github.com/tradalia/core/req"
 	"github.com/tradalia/portfolio-trader/pkg/db"
 )
@@ -38,6 +39,18 @@ func Trunc2d(value float64) float64 {
 	return float64(int(math.Floor(value * 100))) / 100
 }
 
+//=============================================================================
+//--- Like Trunc2d, but infinite and NaN values (that cannot be marshalled to
+//--- JSON) become 0
+
+func TruncFinite(value float64) float64 {
+	if math.IsInf(value, 0) || math.IsNaN(value) {
+		return 0
+	}
+
+	return Trunc2d(value)
+}
+
 //=============================================================================
 
 func GetLocation(timezone string, ts *db.TradingSystem) (*time.Location, error) {
@@ -62,6 +75,65 @@ func ToNonZeroDailyReturnSlice(list *[]db.DailyReturn) []float64 {
 	return res
 }
 
+//=============================================================================
+//--- Builds the daily (net) returns from the first to the last day, adding
+//--- zero returns for weekdays without trading. Weekend days are kept only
+//--- if they have a return
+
+func BuildDailyReturns(list *[]db.DailyReturn, costPerOper float64) ([]datatype.IntDate, []float64) {
+	return buildDailyReturns(list, func(dr *db.DailyReturn) float64 {
+		return dr.GrossProfit - 2 * costPerOper * float64(dr.Trades)
+	})
+}
+
+//=============================================================================
+//--- Same as BuildDailyReturns but returns can belong to different trading
+//--- systems. Returns of the same day are summed
+
+func BuildNetDailyReturns(list *[]db.DailyReturn, pc *ProfitCalculator) ([]datatype.IntDate, []float64) {
+	return buildDailyReturns(list, pc.NetDailyReturn)
+}
+
+//=============================================================================
+
+func buildDailyReturns(list *[]db.DailyReturn, netOf func(dr *db.DailyReturn) float64) ([]datatype.IntDate, []float64) {
+	var days    []datatype.IntDate
+	var returns []float64
+
+	if list == nil || len(*list) == 0 {
+		return days, returns
+	}
+
+	dayMap := map[datatype.IntDate]float64{}
+	first  := (*list)[0].Day
+	last   := first
+
+	for i := range *list {
+		dr := &(*list)[i]
+		dayMap[dr.Day] += netOf(dr)
+
+		if dr.Day < first {
+			first = dr.Day
+		}
+
+		if dr.Day > last {
+			last = dr.Day
+		}
+	}
+
+	for day := first; day <= last; day = day.AddDays(1) {
+		value, ok := dayMap[day]
+		wd := day.ToDateTime(false, time.UTC).Weekday()
+
+		if ok || (wd != time.Saturday && wd != time.Sunday) {
+			days    = append(days,    day)
+			returns = append(returns, value)
+		}
+	}
+
+	return days, returns
+}
+
 //=============================================================================
 
 func CalcRisk(trades *[]db.Trade) (float64, error) {
@@ -78,6 +78,32 @@ func FindDailyReturnsByTsIdFromTime(tx *gorm.DB, tsId uint, fromTime *time.Time,
 
 //=============================================================================
 
+func FindDailyReturnsByTsIdsFromTime(tx *gorm.DB, tsIds []uint, fromTime *time.Time, toTime *time.Time) (*[]DailyReturn, error) {
+	to   := datatype.Today(time.UTC)
+	from := to.AddDays(-50 * 365)
+
+	if fromTime != nil {
+		from = datatype.ToIntDate(fromTime)
+	}
+
+	if toTime != nil {
+		to = datatype.ToIntDate(toTime)
+	}
+
+	var list []DailyReturn
+
+	query := "trading_system_id in ? and day >= ? and day <= ?"
+	res   := tx.Order("day,trading_system_id").Find(&list, query, tsIds, from, to)
+
+	if res.Error != nil {
+		return nil, req.NewServerErrorByError(res.Error)
+	}
+
+	return &list, nil
+}
+
+//=============================================================================
+
 func AddDailyReturn(tx *gorm.DB, dr *DailyReturn) error {
 	err := tx.Create(dr).Error
 	return req.NewServerErrorByError(err)
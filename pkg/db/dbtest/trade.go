@@ -0,0 +1,55 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+
+
+package dbtest
+
+import (
+	"time"
+
+	"github.com/tradalia/portfolio-trader/pkg/db"
+)
+
+//=============================================================================
+//--- Fixtures shared by the tests of the business packages
+
+func NewTrade(tsId uint, tradeType string, entry, exit time.Time, grossProfit float64, contracts int) db.Trade {
+	return db.Trade{
+		TradingSystemId: tsId,
+		TradeType      : tradeType,
+		EntryDate      : &entry,
+		ExitDate       : &exit,
+		GrossProfit    : grossProfit,
+		Contracts      : contracts,
+	}
+}
+
+//=============================================================================
+//--- Returns the given day and hour of January 2025, in UTC
+
+func Time(day, hour int) time.Time {
+	return time.Date(2025, 1, day, hour, 0, 0, 0, time.UTC)
+}
+
+//=============================================================================
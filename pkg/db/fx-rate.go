@@ -0,0 +1,97 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+
+
+package db
+
+import (
+	"github.com/tradalia/core/req"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//=============================================================================
+
+func GetUserSetting(tx *gorm.DB, username string) (*UserSetting, error) {
+	var list []UserSetting
+	res := tx.Where("username = ?", username).Find(&list)
+
+	if res.Error != nil {
+		return nil, req.NewServerErrorByError(res.Error)
+	}
+
+	if len(list) == 1 {
+		return &list[0], nil
+	}
+
+	return nil, nil
+}
+
+//=============================================================================
+
+func SetUserSetting(tx *gorm.DB, us *UserSetting) error {
+	return tx.Save(us).Error
+}
+
+//=============================================================================
+
+func GetFxRates(tx *gorm.DB, filter map[string]any, offset int, limit int) (*[]FxRate, error) {
+	var list []FxRate
+	res := tx.Where(filter).Order("day desc, base_currency, quote_currency").Offset(offset).Limit(limit).Find(&list)
+
+	if res.Error != nil {
+		return nil, req.NewServerErrorByError(res.Error)
+	}
+
+	return &list, nil
+}
+
+//=============================================================================
+
+func GetAllFxRates(tx *gorm.DB) (*[]FxRate, error) {
+	var list []FxRate
+	res := tx.Order("day").Find(&list)
+
+	if res.Error != nil {
+		return nil, req.NewServerErrorByError(res.Error)
+	}
+
+	return &list, nil
+}
+
+//=============================================================================
+//--- Rates already present for the same day and pair are replaced
+
+func SetFxRates(tx *gorm.DB, list []FxRate) error {
+	if len(list) == 0 {
+		return nil
+	}
+
+	return tx.Clauses(clause.OnConflict{
+		Columns  : []clause.Column{{ Name: "day" }, { Name: "base_currency" }, { Name: "quote_currency" }},
+		DoUpdates: clause.AssignmentColumns([]string{ "rate" }),
+	}).CreateInBatches(&list, 500).Error
+}
+
+//=============================================================================
@@ -45,6 +45,7 @@ type Portfolio struct {
 	ParentId  uint    `json:"parentId"`
 	Username  string  `json:"username"`
 	Name      string  `json:"name"`
+	Position  int     `json:"position"`
 }
 
 //=============================================================================
@@ -52,11 +53,12 @@ type Portfolio struct {
 type TsStatus int8
 
 const (
-	TsStatusOff     TsStatus = 0
-	TsStatusPaused  TsStatus = 1
-	TsStatusRunning TsStatus = 2
-	TsStatusIdle    TsStatus = 3
-	TsStatusBroken  TsStatus = 4
+	TsStatusOff      TsStatus = 0
+	TsStatusPaused   TsStatus = 1
+	TsStatusRunning  TsStatus = 2
+	TsStatusIdle     TsStatus = 3
+	TsStatusBroken   TsStatus = 4
+	TsStatusDegraded TsStatus = 5
 )
 
 //-----------------------------------------------------------------------------
@@ -113,7 +115,14 @@ type TradingSystem struct {
 	Timezone          string           `json:"timezone"`
 	InSampleFrom      datatype.IntDate `json:"inSampleFrom"`
 	InSampleTo        datatype.IntDate `json:"inSampleTo"`
+	LiveFrom          datatype.IntDate `json:"liveFrom"`
 	EngineCode        string           `json:"engineCode"`
+	DegradationDate   *time.Time       `json:"degradationDate"`
+	DegradationStat   float64          `json:"degradationStat"`
+	DegradationLimit  float64          `json:"degradationLimit"`
+	EnvelopeExceeded  bool             `json:"envelopeExceeded"`
+	EnvelopeDepthPerc float64          `json:"envelopeDepthPerc"`
+	EnvelopeDurPerc   float64          `json:"envelopeDurPerc"`
 }
 
 //=============================================================================
@@ -141,6 +150,66 @@ type TradingFilter struct {
 
 //=============================================================================
 
+type UserSetting struct {
+	Username      string  `json:"username" gorm:"primaryKey"`
+	BaseCurrency  string  `json:"baseCurrency"`
+}
+
+//=============================================================================
+//--- 1 unit of BaseCurrency is worth Rate units of QuoteCurrency (EUR/USD 1.08)
+
+type FxRate struct {
+	Id             uint             `json:"id" gorm:"primaryKey"`
+	Day            datatype.IntDate `json:"day"           gorm:"uniqueIndex:idx_fx_rate"`
+	BaseCurrency   string           `json:"baseCurrency"  gorm:"uniqueIndex:idx_fx_rate"`
+	QuoteCurrency  string           `json:"quoteCurrency" gorm:"uniqueIndex:idx_fx_rate"`
+	Rate           float64          `json:"rate"`
+}
+
+//=============================================================================
+//--- Limits set to 0 are disabled. If TsIds is empty, the action is applied to
+//--- all trading systems of the portfolio (and of its descendants). Breached
+//--- limits stay in BreachedLimits until the user acknowledges them
+
+type PortfolioRiskLimit struct {
+	PortfolioId      uint             `json:"portfolioId" gorm:"primaryKey"`
+	Username         string           `json:"username"`
+	Enabled          bool             `json:"enabled"`
+	StartDate        datatype.IntDate `json:"startDate"`
+	MaxDrawdown      float64          `json:"maxDrawdown"`
+	MaxDailyLoss     float64          `json:"maxDailyLoss"`
+	MaxActiveSystems int              `json:"maxActiveSystems"`
+	MaxMargin        float64          `json:"maxMargin"`
+	Action           string           `json:"action"`
+	TsIds            []uint           `json:"tsIds" gorm:"serializer:json"`
+	Breached         bool             `json:"breached"`
+	BreachedLimits   string           `json:"breachedLimits"`
+	LastCheck        *time.Time       `json:"lastCheck"`
+}
+
+//=============================================================================
+
+const (
+	RiskEventTypeBreach = "breach"
+	RiskEventTypeClear  = "clear"
+)
+
+//-----------------------------------------------------------------------------
+
+type RiskEvent struct {
+	Id           uint       `json:"id" gorm:"primaryKey"`
+	PortfolioId  uint       `json:"portfolioId"`
+	Username     string     `json:"username"`
+	EventTime    time.Time  `json:"eventTime"`
+	Type         string     `json:"type"`
+	Limits       string     `json:"limits"`
+	Reason       string     `json:"reason"`
+	Action       string     `json:"action"`
+	TsIds        []uint     `json:"tsIds" gorm:"serializer:json"`
+}
+
+//=============================================================================
+
 const (
 	TradeTypeLong  = "LO"
 	TradeTypeShort = "SH"
@@ -198,6 +267,11 @@ func (TradingFilter) TableName() string { return "trading_filter" }
 func (Trade)         TableName() string { return "trade"          }
 func (Portfolio)     TableName() string { return "portfolio"      }
 func (DailyReturn)   TableName() string { return "daily_return"   }
+func (RiskEvent)     TableName() string { return "risk_event"     }
+func (UserSetting)   TableName() string { return "user_setting"   }
+func (FxRate)        TableName() string { return "fx_rate"        }
+
+func (PortfolioRiskLimit) TableName() string { return "portfolio_risk_limit" }
 
 //=============================================================================
 //===
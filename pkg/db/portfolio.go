@@ -33,7 +33,7 @@ import (
 
 func GetPortfolios(tx *gorm.DB, filter map[string]any, offset int, limit int) (*[]Portfolio, error) {
 	var list []Portfolio
-	res := tx.Where(filter).Offset(offset).Limit(limit).Find(&list)
+	res := tx.Where(filter).Order("position, name").Offset(offset).Limit(limit).Find(&list)
 
 	if res.Error != nil {
 		return nil, req.NewServerErrorByError(res.Error)
@@ -43,3 +43,77 @@ func GetPortfolios(tx *gorm.DB, filter map[string]any, offset int, limit int) (*
 }
 
 //=============================================================================
+
+func GetPortfolioById(tx *gorm.DB, id uint) (*Portfolio, error) {
+	var list []Portfolio
+	res := tx.Find(&list, id)
+
+	if res.Error != nil {
+		return nil, req.NewServerErrorByError(res.Error)
+	}
+
+	if len(list) == 1 {
+		return &list[0], nil
+	}
+
+	return nil, nil
+}
+
+//=============================================================================
+
+func GetPortfoliosByUser(tx *gorm.DB, username string) (*[]Portfolio, error) {
+	var list []Portfolio
+	res := tx.Where("username = ?", username).Find(&list)
+
+	if res.Error != nil {
+		return nil, req.NewServerErrorByError(res.Error)
+	}
+
+	return &list, nil
+}
+
+//=============================================================================
+
+func CountPortfolioChildren(tx *gorm.DB, id uint) (int64, error) {
+	var count int64
+	res := tx.Model(&Portfolio{}).Where("parent_id = ?", id).Count(&count)
+
+	if res.Error != nil {
+		return 0, req.NewServerErrorByError(res.Error)
+	}
+
+	return count, nil
+}
+
+//=============================================================================
+
+func CountPortfolioTradingSystems(tx *gorm.DB, id uint) (int64, error) {
+	var count int64
+	res := tx.Model(&TradingSystem{}).Where("portfolio_id = ?", id).Count(&count)
+
+	if res.Error != nil {
+		return 0, req.NewServerErrorByError(res.Error)
+	}
+
+	return count, nil
+}
+
+//=============================================================================
+
+func AddPortfolio(tx *gorm.DB, p *Portfolio) error {
+	return tx.Create(p).Error
+}
+
+//=============================================================================
+
+func UpdatePortfolio(tx *gorm.DB, p *Portfolio) error {
+	return tx.Save(p).Error
+}
+
+//=============================================================================
+
+func DeletePortfolio(tx *gorm.DB, id uint) error {
+	return tx.Delete(&Portfolio{}, id).Error
+}
+
+//=============================================================================
@@ -0,0 +1,107 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+
+
+package db
+
+import (
+	"github.com/tradalia/core/req"
+	"gorm.io/gorm"
+)
+
+//=============================================================================
+
+func GetRiskLimitByPortfolioId(tx *gorm.DB, portfolioId uint) (*PortfolioRiskLimit, error) {
+	var list []PortfolioRiskLimit
+	res := tx.Where("portfolio_id = ?", portfolioId).Find(&list)
+
+	if res.Error != nil {
+		return nil, req.NewServerErrorByError(res.Error)
+	}
+
+	if len(list) == 1 {
+		return &list[0], nil
+	}
+
+	return nil, nil
+}
+
+//=============================================================================
+
+func GetRiskLimitsByPortfolioIds(tx *gorm.DB, ids []uint) (*[]PortfolioRiskLimit, error) {
+	var list []PortfolioRiskLimit
+	res := tx.Where("portfolio_id in ? and enabled = ?", ids, true).Find(&list)
+
+	if res.Error != nil {
+		return nil, req.NewServerErrorByError(res.Error)
+	}
+
+	return &list, nil
+}
+
+//=============================================================================
+
+func GetEnabledRiskLimits(tx *gorm.DB) (*[]PortfolioRiskLimit, error) {
+	var list []PortfolioRiskLimit
+	res := tx.Where("enabled = ?", true).Find(&list)
+
+	if res.Error != nil {
+		return nil, req.NewServerErrorByError(res.Error)
+	}
+
+	return &list, nil
+}
+
+//=============================================================================
+
+func SetRiskLimit(tx *gorm.DB, rl *PortfolioRiskLimit) error {
+	return tx.Save(rl).Error
+}
+
+//=============================================================================
+
+func DeleteRiskLimit(tx *gorm.DB, portfolioId uint) error {
+	return tx.Delete(&PortfolioRiskLimit{}, portfolioId).Error
+}
+
+//=============================================================================
+
+func GetRiskEventsByPortfolioId(tx *gorm.DB, portfolioId uint, offset int, limit int) (*[]RiskEvent, error) {
+	var list []RiskEvent
+	res := tx.Where("portfolio_id = ?", portfolioId).Order("event_time desc").Offset(offset).Limit(limit).Find(&list)
+
+	if res.Error != nil {
+		return nil, req.NewServerErrorByError(res.Error)
+	}
+
+	return &list, nil
+}
+
+//=============================================================================
+
+func AddRiskEvent(tx *gorm.DB, e *RiskEvent) error {
+	return tx.Create(e).Error
+}
+
+//=============================================================================
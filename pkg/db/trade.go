@@ -83,6 +83,32 @@ func FindTradesByTsIdFromTime(tx *gorm.DB, tsId uint, fromTime *time.Time, toTim
 
 //=============================================================================
 
+func FindTradesByTsIdsFromTime(tx *gorm.DB, tsIds []uint, fromTime *time.Time, toTime *time.Time) (*[]Trade, error) {
+	to   := time.Now().UTC()
+	from := to.Add(-50 * 365 * 24 * time.Hour)
+
+	if fromTime != nil {
+		from = *fromTime
+	}
+
+	if toTime != nil {
+		to = *toTime
+	}
+
+	var list []Trade
+
+	query := "trading_system_id in ? and exit_date >= ? and exit_date<= ?"
+	res   := tx.Order("exit_date,entry_date,id").Find(&list, query, tsIds, from, to)
+
+	if res.Error != nil {
+		return nil, req.NewServerErrorByError(res.Error)
+	}
+
+	return &list, nil
+}
+
+//=============================================================================
+
 func FindTradesFromTime(tx *gorm.DB, tsIds []uint, fromTime time.Time) (*[]Trade, error) {
 	var list []Trade
 
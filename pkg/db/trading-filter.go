@@ -32,6 +32,22 @@ import (
 //=============================================================================
 
 func GetTradingFilterByTsId(tx *gorm.DB, tsId uint) (*TradingFilter, error) {
+	tf, err := FindTradingFilterByTsId(tx, tsId)
+	if err != nil {
+		return nil, err
+	}
+
+	if tf == nil {
+		return nil, req.NewServerError("Filter not found for tsId=%v",tsId)
+	}
+
+	return tf, nil
+}
+
+//=============================================================================
+//--- Returns nil if the trading system has no stored filter
+
+func FindTradingFilterByTsId(tx *gorm.DB, tsId uint) (*TradingFilter, error) {
 	var list []TradingFilter
 
 	filter := map[string]any{}
@@ -44,7 +60,7 @@ func GetTradingFilterByTsId(tx *gorm.DB, tsId uint) (*TradingFilter, error) {
 	}
 
 	if len(list) == 0 {
-		return nil, req.NewServerError("Filter not found for tsId=%v",tsId)
+		return nil, nil
 	}
 
 	return &list[0], nil
@@ -127,10 +127,40 @@ func GetTradingSystemsInIdle(tx *gorm.DB, days int) (*[]TradingSystem, error) {
 
 //=============================================================================
 
+func GetRunningTradingSystems(tx *gorm.DB) (*[]TradingSystem, error) {
+	var list []TradingSystem
+
+	res := tx.
+		Where("running = ?", true).
+		Find(&list)
+
+	if res.Error != nil {
+		return nil, req.NewServerErrorByError(res.Error)
+	}
+
+	return &list, nil
+}
+
+//=============================================================================
+
 func UpdateTradingSystem(tx *gorm.DB, ts *TradingSystem) error {
 	return tx.Save(ts).Error
 }
 
+//=============================================================================
+//--- Writes only the envelope fields, leaving the status untouched
+
+func UpdateTradingSystemEnvelope(tx *gorm.DB, ts *TradingSystem) error {
+	return tx.Model(&TradingSystem{}).
+		Where("id", ts.Id).
+		Updates(map[string]interface{}{
+			"envelope_exceeded"  : ts.EnvelopeExceeded,
+			"envelope_depth_perc": ts.EnvelopeDepthPerc,
+			"envelope_dur_perc"  : ts.EnvelopeDurPerc,
+			"suggested_action"   : ts.SuggestedAction,
+		}).Error
+}
+
 //=============================================================================
 
 func UpdateDataProductInfo(tx *gorm.DB, dataProductId uint, values map[string]interface{}) error {
@@ -154,3 +184,19 @@ func DeleteTradingSystem(tx *gorm.DB, id uint) error {
 }
 
 //=============================================================================
+
+func GetTradingSystemsByPortfolioIds(tx *gorm.DB, ids []uint) (*[]TradingSystem, error) {
+	var list []TradingSystem
+
+	res := tx.
+		Where("portfolio_id in ?", ids).
+		Find(&list)
+
+	if res.Error != nil {
+		return nil, req.NewServerErrorByError(res.Error)
+	}
+
+	return &list, nil
+}
+
+//=============================================================================
@@ -0,0 +1,126 @@
+//=============================================================================
+/*
+Copyright © 2026 Andrea Carboni andrea.carboni71@gmail.com
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+//=============================================================================
+
+
+package service
+
+import (
+	"strings"
+
+	"github.com/tradalia/core/auth"
+	"github.com/tradalia/core/req"
+	"github.com/tradalia/portfolio-trader/pkg/business"
+	"github.com/tradalia/portfolio-trader/pkg/db"
+	"gorm.io/gorm"
+)
+
+//=============================================================================
+
+func getUserSetting(c *auth.Context) {
+	err := db.RunInTransaction(func(tx *gorm.DB) error {
+		us, err := business.GetUserSetting(tx, c)
+
+		if err != nil {
+			return err
+		}
+
+		return c.ReturnObject(us)
+	})
+
+	c.ReturnError(err)
+}
+
+//=============================================================================
+
+func setUserSetting(c *auth.Context) {
+	usr := business.UserSettingRequest{}
+	err := c.BindParamsFromBody(&usr)
+
+	if err == nil {
+		err = db.RunInTransaction(func(tx *gorm.DB) error {
+			us, err := business.SetUserSetting(tx, c, &usr)
+
+			if err != nil {
+				return err
+			}
+
+			return c.ReturnObject(us)
+		})
+	}
+
+	c.ReturnError(err)
+}
+
+//=============================================================================
+
+func getFxRates(c *auth.Context) {
+	filter := map[string]any{}
+	offset, limit, err := c.GetPagingParams()
+
+	if base := c.GetParamAsString("base", ""); base != "" {
+		filter["base_currency"] = strings.ToUpper(base)
+	}
+
+	if quote := c.GetParamAsString("quote", ""); quote != "" {
+		filter["quote_currency"] = strings.ToUpper(quote)
+	}
+
+	if err == nil {
+		err = db.RunInTransaction(func(tx *gorm.DB) error {
+			list, err := business.GetFxRates(tx, c, filter, offset, limit)
+
+			if err != nil {
+				return err
+			}
+
+			return c.ReturnList(list, offset, limit, len(*list))
+		})
+	}
+
+	c.ReturnError(err)
+}
+
+//=============================================================================
+
+func importFxRates(c *auth.Context) {
+	data, err := c.Gin.GetRawData()
+
+	if err != nil {
+		err = req.NewBadRequestError("Cannot read CSV data: %v", err.Error())
+	} else {
+		err = db.RunInTransaction(func(tx *gorm.DB) error {
+			res, err := business.ImportFxRates(tx, c, data)
+
+			if err != nil {
+				return err
+			}
+
+			return c.ReturnObject(res)
+		})
+	}
+
+	c.ReturnError(err)
+}
+
+//=============================================================================
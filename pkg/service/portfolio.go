@@ -26,7 +26,15 @@ package service
 
 import (
 	"github.com/tradalia/core/auth"
+	"github.com/tradalia/core/msg"
 	"github.com/tradalia/portfolio-trader/pkg/business"
+	"github.com/tradalia/portfolio-trader/pkg/business/correlation"
+	"github.com/tradalia/portfolio-trader/pkg/business/exposure"
+	"github.com/tradalia/portfolio-trader/pkg/business/grouping"
+	"github.com/tradalia/portfolio-trader/pkg/business/marginal"
+	"github.com/tradalia/portfolio-trader/pkg/business/optimizer"
+	"github.com/tradalia/portfolio-trader/pkg/business/performance"
+	"github.com/tradalia/portfolio-trader/pkg/business/risk"
 	"github.com/tradalia/portfolio-trader/pkg/db"
 	"gorm.io/gorm"
 )
@@ -75,13 +83,82 @@ func getPortfolioTree(c *auth.Context) {
 
 //=============================================================================
 
+func addPortfolio(c *auth.Context) {
+	req := business.PortfolioRequest{}
+	err := c.BindParamsFromBody(&req)
+
+	if err == nil {
+		var p *db.Portfolio
+		err = db.RunInTransaction(func(tx *gorm.DB) error {
+			p, err = business.AddPortfolio(tx, c, &req)
+			return err
+		})
+
+		if err == nil {
+			business.SendPortfolioMessage(c, msg.TypeCreate, p)
+			err = c.ReturnObject(p)
+		}
+	}
+
+	c.ReturnError(err)
+}
+
+//=============================================================================
+
+func updatePortfolio(c *auth.Context) {
+	id, err := c.GetIdFromUrl()
+
+	if err == nil {
+		req := business.PortfolioRequest{}
+		err = c.BindParamsFromBody(&req)
+
+		if err == nil {
+			var p *db.Portfolio
+			err = db.RunInTransaction(func(tx *gorm.DB) error {
+				p, err = business.UpdatePortfolio(tx, c, id, &req)
+				return err
+			})
+
+			if err == nil {
+				business.SendPortfolioMessage(c, msg.TypeUpdate, p)
+				err = c.ReturnObject(p)
+			}
+		}
+	}
+
+	c.ReturnError(err)
+}
+
+//=============================================================================
+
+func deletePortfolio(c *auth.Context) {
+	id, err := c.GetIdFromUrl()
+
+	if err == nil {
+		var p *db.Portfolio
+		err = db.RunInTransaction(func(tx *gorm.DB) error {
+			p, err = business.DeletePortfolio(tx, c, id)
+			return err
+		})
+
+		if err == nil {
+			business.SendPortfolioMessage(c, msg.TypeDelete, p)
+			err = c.ReturnObject(p)
+		}
+	}
+
+	c.ReturnError(err)
+}
+
+//=============================================================================
+
 func getPortfolioMonitoring(c *auth.Context) {
 	params := business.PortfolioMonitoringParams{}
 	err    := c.BindParamsFromBody(&params)
 
 	if err == nil {
 		err = db.RunInTransaction(func(tx *gorm.DB) error {
-			result, err := business.GetPortfolioMonitoring(tx, &params)
+			result, err := business.GetPortfolioMonitoring(tx, c, &params)
 
 			if err != nil {
 				return err
@@ -95,3 +172,277 @@ func getPortfolioMonitoring(c *auth.Context) {
 }
 
 //=============================================================================
+
+func runPortfolioPerformanceAnalysis(c *auth.Context) {
+	id, err := c.GetIdFromUrl()
+
+	if err == nil {
+		req := performance.AnalysisRequest{}
+		err = c.BindParamsFromBody(&req)
+
+		if err == nil {
+			err = db.RunInTransaction(func(tx *gorm.DB) error {
+				rep, err := business.RunPortfolioPerformanceAnalysis(tx, c, id, &req)
+
+				if err != nil {
+					return err
+				}
+
+				return c.ReturnObject(rep)
+			})
+		}
+	}
+
+	c.ReturnError(err)
+}
+
+//=============================================================================
+
+func runCorrelationAnalysis(c *auth.Context) {
+	req := correlation.AnalysisRequest{}
+	err := c.BindParamsFromBody(&req)
+
+	if err == nil {
+		err = db.RunInTransaction(func(tx *gorm.DB) error {
+			rep, err := business.RunCorrelationAnalysis(tx, c, &req)
+
+			if err != nil {
+				return err
+			}
+
+			return c.ReturnObject(rep)
+		})
+	}
+
+	c.ReturnError(err)
+}
+
+//=============================================================================
+
+func runMarginalAnalysis(c *auth.Context) {
+	req := marginal.AnalysisRequest{}
+	err := c.BindParamsFromBody(&req)
+
+	if err == nil {
+		err = db.RunInTransaction(func(tx *gorm.DB) error {
+			rep, err := business.RunMarginalAnalysis(tx, c, &req)
+
+			if err != nil {
+				return err
+			}
+
+			return c.ReturnObject(rep)
+		})
+	}
+
+	c.ReturnError(err)
+}
+
+//=============================================================================
+
+func runExposureAnalysis(c *auth.Context) {
+	req := exposure.AnalysisRequest{}
+	err := c.BindParamsFromBody(&req)
+
+	if err == nil {
+		err = db.RunInTransaction(func(tx *gorm.DB) error {
+			rep, err := business.RunExposureAnalysis(tx, c, &req)
+
+			if err != nil {
+				return err
+			}
+
+			return c.ReturnObject(rep)
+		})
+	}
+
+	c.ReturnError(err)
+}
+
+//=============================================================================
+
+func runGroupAnalysis(c *auth.Context) {
+	req := grouping.AnalysisRequest{}
+	err := c.BindParamsFromBody(&req)
+
+	if err == nil {
+		err = db.RunInTransaction(func(tx *gorm.DB) error {
+			rep, err := business.RunGroupAnalysis(tx, c, &req)
+
+			if err != nil {
+				return err
+			}
+
+			return c.ReturnObject(rep)
+		})
+	}
+
+	c.ReturnError(err)
+}
+
+//=============================================================================
+//===
+//=== Portfolio optimization
+//===
+//=============================================================================
+
+func startPortfolioOptimization(c *auth.Context) {
+	req := optimizer.OptimizationRequest{}
+	err := c.BindParamsFromBody(&req)
+
+	if err == nil {
+		err = db.RunInTransaction(func(tx *gorm.DB) error {
+			err := business.StartPortfolioOptimization(tx, c, &req)
+
+			if err != nil {
+				return err
+			}
+
+			return c.ReturnObject(NewStatusOkResponse())
+		})
+	}
+
+	c.ReturnError(err)
+}
+
+//=============================================================================
+
+func stopPortfolioOptimization(c *auth.Context) {
+	err := business.StopPortfolioOptimization(c)
+
+	if err != nil {
+		c.ReturnError(err)
+	} else {
+		_ = c.ReturnObject(NewStatusOkResponse())
+	}
+}
+
+//=============================================================================
+
+func getPortfolioOptimizationInfo(c *auth.Context) {
+	res, err := business.GetPortfolioOptimizationInfo(c)
+
+	if err != nil {
+		c.ReturnError(err)
+	} else {
+		_ = c.ReturnObject(res)
+	}
+}
+
+//=============================================================================
+//===
+//=== Risk limits
+//===
+//=============================================================================
+
+func getRiskLimit(c *auth.Context) {
+	id, err := c.GetIdFromUrl()
+
+	if err == nil {
+		err = db.RunInTransaction(func(tx *gorm.DB) error {
+			res, err := business.GetRiskLimit(tx, c, id)
+
+			if err != nil {
+				return err
+			}
+
+			return c.ReturnObject(res)
+		})
+	}
+
+	c.ReturnError(err)
+}
+
+//=============================================================================
+
+func setRiskLimit(c *auth.Context) {
+	id, err := c.GetIdFromUrl()
+
+	if err == nil {
+		req := risk.LimitRequest{}
+		err = c.BindParamsFromBody(&req)
+
+		if err == nil {
+			var res *risk.LimitResponse
+			err = db.RunInTransaction(func(tx *gorm.DB) error {
+				res, err = business.SetRiskLimit(tx, c, id, &req)
+				return err
+			})
+
+			if err == nil {
+				business.SendRiskEvent(res)
+				err = c.ReturnObject(res)
+			}
+		}
+	}
+
+	c.ReturnError(err)
+}
+
+//=============================================================================
+
+func acknowledgeRiskLimit(c *auth.Context) {
+	id, err := c.GetIdFromUrl()
+
+	if err == nil {
+		var res *risk.LimitResponse
+		err = db.RunInTransaction(func(tx *gorm.DB) error {
+			res, err = business.AcknowledgeRiskLimit(tx, c, id)
+			return err
+		})
+
+		if err == nil {
+			business.SendRiskEvent(res)
+			err = c.ReturnObject(res)
+		}
+	}
+
+	c.ReturnError(err)
+}
+
+//=============================================================================
+
+func deleteRiskLimit(c *auth.Context) {
+	id, err := c.GetIdFromUrl()
+
+	if err == nil {
+		err = db.RunInTransaction(func(tx *gorm.DB) error {
+			err := business.DeleteRiskLimit(tx, c, id)
+
+			if err != nil {
+				return err
+			}
+
+			return c.ReturnObject(NewStatusOkResponse())
+		})
+	}
+
+	c.ReturnError(err)
+}
+
+//=============================================================================
+
+func getRiskEvents(c *auth.Context) {
+	id, err := c.GetIdFromUrl()
+
+	if err == nil {
+		var offset, limit int
+		offset, limit, err = c.GetPagingParams()
+
+		if err == nil {
+			err = db.RunInTransaction(func(tx *gorm.DB) error {
+				list, err := business.GetRiskEvents(tx, c, id, offset, limit)
+
+				if err != nil {
+					return err
+				}
+
+				return c.ReturnList(list, offset, limit, len(*list))
+			})
+		}
+	}
+
+	c.ReturnError(err)
+}
+
+//=============================================================================
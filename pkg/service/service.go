@@ -51,8 +51,10 @@ func Init(router *gin.Engine, cfg *app.Config, logger *slog.Logger) {
 	router.POST  ("/api/portfolio/v1/trading-systems/:id/running",             ctrl.Secure(setTradingSystemRunning,   roles.Admin_User_Service))
 	router.POST  ("/api/portfolio/v1/trading-systems/:id/activation",          ctrl.Secure(setTradingSystemActivation,roles.Admin_User_Service))
 	router.POST  ("/api/portfolio/v1/trading-systems/:id/active",              ctrl.Secure(setTradingSystemActive,    roles.Admin_User_Service))
+	router.POST  ("/api/portfolio/v1/trading-systems/:id/portfolio",           ctrl.Secure(setTradingSystemPortfolio, roles.Admin_User_Service))
 	router.POST  ("/api/portfolio/v1/trading-systems/:id/performance-analysis",ctrl.Secure(runPerformanceAnalysis,    roles.Admin_User_Service))
 	router.POST  ("/api/portfolio/v1/trading-systems/:id/quality-analysis",    ctrl.Secure(runQualityAnalysis,        roles.Admin_User_Service))
+	router.POST  ("/api/portfolio/v1/trading-systems/:id/degradation-analysis",ctrl.Secure(runDegradationAnalysis,    roles.Admin_User_Service))
 
 	router.GET   ("/api/portfolio/v1/trading-systems/:id/filter-optimization", ctrl.Secure(getFilterOptimizationInfo, roles.Admin_User_Service))
 	router.POST  ("/api/portfolio/v1/trading-systems/:id/filter-optimization", ctrl.Secure(startFilterOptimization,   roles.Admin_User_Service))
@@ -63,8 +65,29 @@ func Init(router *gin.Engine, cfg *app.Config, logger *slog.Logger) {
 	router.DELETE("/api/portfolio/v1/trading-systems/:id/simulation",          ctrl.Secure(stopSimulation,            roles.Admin_User_Service))
 
 	router.GET   ("/api/inventory/v1/portfolios",                              ctrl.Secure(getPortfolios,             roles.Admin_User_Service))
+	router.POST  ("/api/inventory/v1/portfolios",                              ctrl.Secure(addPortfolio,              roles.Admin_User_Service))
+	router.PUT   ("/api/inventory/v1/portfolios/:id",                          ctrl.Secure(updatePortfolio,           roles.Admin_User_Service))
+	router.DELETE("/api/inventory/v1/portfolios/:id",                          ctrl.Secure(deletePortfolio,           roles.Admin_User_Service))
 	router.GET   ("/api/inventory/v1/portfolio/tree",                          ctrl.Secure(getPortfolioTree,          roles.Admin_User_Service))
 	router.POST  ("/api/portfolio/v1/portfolio/monitoring",                    ctrl.Secure(getPortfolioMonitoring,    roles.Admin_User_Service))
+	router.POST  ("/api/portfolio/v1/portfolio/correlation-analysis",          ctrl.Secure(runCorrelationAnalysis,    roles.Admin_User_Service))
+	router.POST  ("/api/portfolio/v1/portfolio/marginal-analysis",             ctrl.Secure(runMarginalAnalysis,       roles.Admin_User_Service))
+	router.POST  ("/api/portfolio/v1/portfolio/exposure-analysis",             ctrl.Secure(runExposureAnalysis,       roles.Admin_User_Service))
+	router.POST  ("/api/portfolio/v1/portfolio/group-analysis",                ctrl.Secure(runGroupAnalysis,          roles.Admin_User_Service))
+	router.GET   ("/api/portfolio/v1/portfolio/optimization",                  ctrl.Secure(getPortfolioOptimizationInfo, roles.Admin_User_Service))
+	router.POST  ("/api/portfolio/v1/portfolio/optimization",                  ctrl.Secure(startPortfolioOptimization,   roles.Admin_User_Service))
+	router.DELETE("/api/portfolio/v1/portfolio/optimization",                  ctrl.Secure(stopPortfolioOptimization,    roles.Admin_User_Service))
+	router.POST  ("/api/portfolio/v1/portfolio/:id/performance-analysis",      ctrl.Secure(runPortfolioPerformanceAnalysis, roles.Admin_User_Service))
+	router.GET   ("/api/portfolio/v1/portfolio/:id/risk-limit",                ctrl.Secure(getRiskLimit,              roles.Admin_User_Service))
+	router.POST  ("/api/portfolio/v1/portfolio/:id/risk-limit",                ctrl.Secure(setRiskLimit,              roles.Admin_User_Service))
+	router.DELETE("/api/portfolio/v1/portfolio/:id/risk-limit",                ctrl.Secure(deleteRiskLimit,           roles.Admin_User_Service))
+	router.POST  ("/api/portfolio/v1/portfolio/:id/risk-limit/acknowledge",    ctrl.Secure(acknowledgeRiskLimit,      roles.Admin_User_Service))
+	router.GET   ("/api/portfolio/v1/portfolio/:id/risk-events",               ctrl.Secure(getRiskEvents,             roles.Admin_User_Service))
+
+	router.GET   ("/api/portfolio/v1/settings",                                ctrl.Secure(getUserSetting,            roles.Admin_User_Service))
+	router.POST  ("/api/portfolio/v1/settings",                                ctrl.Secure(setUserSetting,            roles.Admin_User_Service))
+	router.GET   ("/api/portfolio/v1/fx-rates",                                ctrl.Secure(getFxRates,                roles.Admin_User_Service))
+	router.POST  ("/api/portfolio/v1/fx-rates/import",                         ctrl.Secure(importFxRates,             roles.Admin_Service))
 }
 
 //=============================================================================
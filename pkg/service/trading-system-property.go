@@ -132,3 +132,32 @@ func setTradingSystemActive(c *auth.Context) {
 }
 
 //=============================================================================
+
+func setTradingSystemPortfolio(c *auth.Context) {
+	tsId, err := c.GetIdFromUrl()
+
+	if err == nil {
+		req := business.TradingSystemPortfolioRequest{}
+		err = c.BindParamsFromBody(&req)
+
+		if err == nil {
+			var rep *business.TradingSystemPropertyResponse
+			err = db.RunInTransaction(func(tx *gorm.DB) error {
+				rep, err = business.SetTradingSystemPortfolio(tx, c, tsId, &req)
+				return err
+			})
+
+			if err == nil {
+				if rep.Status == business.ResponseStatusOk {
+					business.SendPortfolioAssignmentMessage(c, rep.TradingSystem)
+				}
+
+				err = c.ReturnObject(rep)
+			}
+		}
+	}
+
+	c.ReturnError(err)
+}
+
+//=============================================================================
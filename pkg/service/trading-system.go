@@ -27,6 +27,7 @@ package service
 import (
 	"github.com/tradalia/core/auth"
 	"github.com/tradalia/portfolio-trader/pkg/business"
+	"github.com/tradalia/portfolio-trader/pkg/business/degradation"
 	"github.com/tradalia/portfolio-trader/pkg/business/filter"
 	"github.com/tradalia/portfolio-trader/pkg/business/performance"
 	"github.com/tradalia/portfolio-trader/pkg/business/quality"
@@ -240,6 +241,31 @@ func runQualityAnalysis(c *auth.Context) {
 	c.ReturnError(err)
 }
 
+//=============================================================================
+
+func runDegradationAnalysis(c *auth.Context) {
+	tsId, err := c.GetIdFromUrl()
+
+	if err == nil {
+		req := degradation.AnalysisRequest{}
+		err = c.BindParamsFromBody(&req)
+
+		if err == nil {
+			err = db.RunInTransaction(func(tx *gorm.DB) error {
+				res, err := business.RunDegradationAnalysis(tx, c, tsId, &req)
+
+				if err != nil {
+					return err
+				}
+
+				return c.ReturnObject(res)
+			})
+		}
+	}
+
+	c.ReturnError(err)
+}
+
 //=============================================================================
 //===
 //=== Filter optimization